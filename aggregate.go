@@ -0,0 +1,260 @@
+package aorm
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AggregateExpr is the aggregate function of field
+type AggregateExpr struct {
+	fn       string
+	field    string
+	alias    string
+	distinct bool
+}
+
+func newAggregateExpr(fn, field string) *AggregateExpr {
+	return &AggregateExpr{fn: fn, field: field}
+}
+
+// Sum returns the SUM aggregate of field
+func Sum(field string) *AggregateExpr {
+	return newAggregateExpr("SUM", field)
+}
+
+// Avg returns the AVG aggregate of field
+func Avg(field string) *AggregateExpr {
+	return newAggregateExpr("AVG", field)
+}
+
+// Min returns the MIN aggregate of field
+func Min(field string) *AggregateExpr {
+	return newAggregateExpr("MIN", field)
+}
+
+// Max returns the MAX aggregate of field
+func Max(field string) *AggregateExpr {
+	return newAggregateExpr("MAX", field)
+}
+
+// Count returns the COUNT aggregate of field, or of rows if field is `*`
+func Count(field string) *AggregateExpr {
+	return newAggregateExpr("COUNT", field)
+}
+
+// CountDistinct returns the COUNT aggregate of distinct values of field
+func CountDistinct(field string) *AggregateExpr {
+	return Count(field).Distinct()
+}
+
+// As sets the result name. The name is converted to column name, so it matches the struct field of same name
+// when scanned into struct. Defaults to function and field names, e.g. `sum_total` for Sum("Total").
+func (this AggregateExpr) As(alias string) *AggregateExpr {
+	this.alias = alias
+	return &this
+}
+
+// Distinct aggregates the distinct values only
+func (this AggregateExpr) Distinct() *AggregateExpr {
+	this.distinct = true
+	return &this
+}
+
+// Alias returns the column name of result
+func (this *AggregateExpr) Alias() string {
+	if this.alias != "" {
+		return ToDBName(this.alias)
+	}
+	if this.field == "*" {
+		return strings.ToLower(this.fn)
+	}
+	return strings.ToLower(this.fn) + "_" + ToDBName(this.field)
+}
+
+// Select returns the select expression of aggregate. The field name is resolved to column and the select
+// wrapper of field, e.g. the types.Money cast, is applied to the result.
+func (this *AggregateExpr) Select(scope *Scope) string {
+	var (
+		arg   = this.field
+		field *StructField
+	)
+	if arg != "*" {
+		var ok bool
+		if field, ok = scope.Struct().FieldByName(arg); ok {
+			arg = scope.QuotedTableName() + "." + scope.Quote(field.DBName)
+		}
+	}
+	if this.distinct {
+		arg = "DISTINCT " + arg
+	}
+	query := &Query{Query: this.fn + "(" + arg + ")"}
+	if field != nil && field.SelectWraper != nil && this.fn != "COUNT" {
+		query = field.SelectWraper.SelectWrap(field, scope, query)
+	}
+	return query.Query + " AS " + scope.Quote(this.Alias())
+}
+
+// Aggregation is the query of aggregates, optionally grouped
+type Aggregation struct {
+	db     *DB
+	exprs  []*AggregateExpr
+	groups []string
+	having []*Clause
+}
+
+// Aggregate creates the aggregation query of model
+//
+//	var totals []struct {
+//		CustomerID int
+//		Total      types.Money
+//		Count      int
+//	}
+//	err := db.Model(&Order{}).Aggregate(aorm.Sum("Total").As("Total"), aorm.Count("*")).
+//		GroupBy("CustomerID").
+//		Scan(&totals)
+func (s *DB) Aggregate(exprs ...*AggregateExpr) *Aggregation {
+	return &Aggregation{db: s, exprs: exprs}
+}
+
+// GroupBy groups the results by fields, or columns, that are selected too
+func (this *Aggregation) GroupBy(fields ...string) *Aggregation {
+	this.groups = append(this.groups, fields...)
+	return this
+}
+
+// Having filters the grouped results
+func (this *Aggregation) Having(query interface{}, args ...interface{}) *Aggregation {
+	this.having = append(this.having, &Clause{query, args})
+	return this
+}
+
+// Query returns the db of aggregation query
+func (this *Aggregation) Query() *DB {
+	var (
+		scope           = this.db.NewScope(this.db.Val)
+		selects, groups []string
+	)
+	for _, name := range this.groups {
+		column := name
+		if field, ok := scope.Struct().FieldByName(name); ok {
+			column = scope.QuotedTableName() + "." + scope.Quote(field.DBName)
+		}
+		selects = append(selects, column)
+		groups = append(groups, column)
+	}
+	for _, expr := range this.exprs {
+		selects = append(selects, expr.Select(scope))
+	}
+
+	db := this.db.Select(strings.Join(selects, ", "))
+	if len(groups) > 0 {
+		db = db.Group(strings.Join(groups, ", "))
+	}
+	for _, having := range this.having {
+		db = db.Having(having.Query, having.Args...)
+	}
+	return db
+}
+
+// Scan runs the query and scans the results into dest, that is a pointer to struct, map[string]interface{} or
+// slice of them.
+func (this *Aggregation) Scan(dest interface{}) (err error) {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.Errorf("aggregate: destination must be a pointer, but got %T", dest)
+	}
+	if this.db.Val == nil {
+		return errors.New("aggregate: model is not set, see DB.Model")
+	}
+	if len(this.exprs) == 0 && len(this.groups) == 0 {
+		return errors.New("aggregate: no aggregates")
+	}
+
+	rows, err := this.Query().Rows()
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	rv = rv.Elem()
+	switch rv.Kind() {
+	case reflect.Slice:
+		var (
+			elemType = rv.Type().Elem()
+			isPtr    = elemType.Kind() == reflect.Ptr
+			result   = reflect.MakeSlice(rv.Type(), 0, 0)
+		)
+		if isPtr {
+			elemType = elemType.Elem()
+		}
+		for rows.Next() {
+			elem := reflect.New(elemType)
+			if err = this.scanRow(rows, elem); err != nil {
+				return
+			}
+			if !isPtr {
+				elem = elem.Elem()
+			}
+			result = reflect.Append(result, elem)
+		}
+		rv.Set(result)
+	default:
+		if rows.Next() {
+			err = this.scanRow(rows, rv.Addr())
+		}
+	}
+	if err == nil {
+		err = rows.Err()
+	}
+	return
+}
+
+func (this *Aggregation) scanRow(rows *sql.Rows, dest reflect.Value) (err error) {
+	switch dest.Elem().Kind() {
+	case reflect.Map:
+		var m map[string]interface{}
+		if m, err = scanMap(rows); err != nil {
+			return
+		}
+		if dest.Elem().Type() != reflect.TypeOf(m) {
+			return errors.Errorf("aggregate: unsupported map type %s", dest.Elem().Type())
+		}
+		dest.Elem().Set(reflect.ValueOf(m))
+		return
+	case reflect.Struct:
+		return this.db.ScanRows(rows, dest.Interface())
+	default:
+		// single value
+		return rows.Scan(dest.Interface())
+	}
+}
+
+// scanMap scans the current row into map of columns. The []byte values are converted to string.
+func scanMap(rows *sql.Rows) (m map[string]interface{}, err error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return
+	}
+	var (
+		values   = make([]interface{}, len(columns))
+		pointers = make([]interface{}, len(columns))
+	)
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err = rows.Scan(pointers...); err != nil {
+		return
+	}
+	m = make(map[string]interface{}, len(columns))
+	for i, column := range columns {
+		if b, ok := values[i].([]byte); ok {
+			m[column] = string(b)
+		} else {
+			m[column] = values[i]
+		}
+	}
+	return
+}
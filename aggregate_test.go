@@ -0,0 +1,46 @@
+package aorm_test
+
+import (
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+	"github.com/moisespsena-go/aorm/aormtest"
+	"github.com/moisespsena-go/aorm/types"
+)
+
+type AggOrder struct {
+	ID         int
+	CustomerID int
+	Total      types.Money
+}
+
+func TestAggregate(t *testing.T) {
+	db, mock := aormtest.New(t, "postgres")
+	mock.ExpectRegexp(`^SELECT .*"aorm_test_agg_orders"\."customer_id", SUM\("aorm_test_agg_orders"\."total"\)::FLOAT8 AS "total", COUNT\(\*\) AS "count" FROM "aorm_test_agg_orders" .*GROUP BY "aorm_test_agg_orders"\."customer_id"`).
+		WillReturnRows(aormtest.NewRows("customer_id", "total", "count").AddRow(1, 10.5, 2).AddRow(2, 3.0, 1))
+	mock.ExpectRegexp(`^SELECT .*MAX\("aorm_test_agg_orders"\."id"\) AS "max_id" FROM "aorm_test_agg_orders"`).
+		WillReturnRows(aormtest.NewRows("max_id").AddRow(7))
+
+	var totals []struct {
+		CustomerID int
+		Total      types.Money
+		Count      int
+	}
+	err := db.Model(&AggOrder{}).Aggregate(aorm.Sum("Total").As("Total"), aorm.Count("*")).
+		GroupBy("CustomerID").
+		Scan(&totals)
+	if err != nil {
+		t.Fatalf("no error should happen when aggregate, but got %v", err)
+	}
+	if len(totals) != 2 || totals[0].CustomerID != 1 || totals[0].Total != 10.5 || totals[0].Count != 2 {
+		t.Errorf("aggregates should be scanned into structs, but got %+v", totals)
+	}
+
+	var m map[string]interface{}
+	if err = db.Model(&AggOrder{}).Aggregate(aorm.Max("ID")).Scan(&m); err != nil {
+		t.Fatalf("no error should happen when aggregate, but got %v", err)
+	}
+	if m["max_id"] != int64(7) {
+		t.Errorf("aggregate should be scanned into map, but got %v", m)
+	}
+}
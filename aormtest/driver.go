@@ -0,0 +1,173 @@
+package aormtest
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DriverName is the name of database/sql driver of mocks
+const DriverName = "aormtest"
+
+var (
+	mocks   sync.Map
+	mocksID int64
+)
+
+func init() {
+	sql.Register(DriverName, mockDriver{})
+}
+
+func registerMock(mock *Mock) (dsn string) {
+	dsn = strconv.FormatInt(atomic.AddInt64(&mocksID, 1), 10)
+	mock.dsn = dsn
+	mocks.Store(dsn, mock)
+	return
+}
+
+type mockDriver struct{}
+
+func (mockDriver) Open(dsn string) (driver.Conn, error) {
+	mock, ok := mocks.Load(dsn)
+	if !ok {
+		return nil, fmt.Errorf("aormtest: mock %q does not exists", dsn)
+	}
+	return &conn{mock.(*Mock)}, nil
+}
+
+type conn struct {
+	mock *Mock
+}
+
+func (this *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{this, query}, nil
+}
+
+func (this *conn) Close() error {
+	return nil
+}
+
+func (this *conn) Begin() (driver.Tx, error) {
+	return this.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx records the transaction options into BEGIN statement, e.g. `BEGIN ISOLATION LEVEL SERIALIZABLE`
+func (this *conn) BeginTx(_ context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	query := "BEGIN"
+	if level := sql.IsolationLevel(opts.Isolation); level != sql.LevelDefault {
+		query += " ISOLATION LEVEL " + strings.ToUpper(level.String())
+	}
+	if opts.ReadOnly {
+		query += " READ ONLY"
+	}
+	if _, err := this.mock.handle(query, nil, false); err != nil {
+		return nil, err
+	}
+	return &tx{this}, nil
+}
+
+func (this *conn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, err := this.mock.handle(query, args, false)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return result{}, nil
+	}
+	return result{e.lastInsertId, e.rowsAffected}, nil
+}
+
+func (this *conn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	e, err := this.mock.handle(query, args, true)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return &rows{}, nil
+	}
+	return &rows{columns: e.columns, values: e.rows}, nil
+}
+
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (this *stmt) Close() error {
+	return nil
+}
+
+func (this *stmt) NumInput() int {
+	return -1
+}
+
+func (this *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return this.conn.ExecContext(context.Background(), this.query, namedValues(args))
+}
+
+func (this *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return this.conn.QueryContext(context.Background(), this.query, namedValues(args))
+}
+
+type tx struct {
+	conn *conn
+}
+
+func (this *tx) Commit() error {
+	_, err := this.conn.mock.handle("COMMIT", nil, false)
+	return err
+}
+
+func (this *tx) Rollback() error {
+	_, err := this.conn.mock.handle("ROLLBACK", nil, false)
+	return err
+}
+
+type result struct {
+	lastInsertId, rowsAffected int64
+}
+
+func (this result) LastInsertId() (int64, error) {
+	return this.lastInsertId, nil
+}
+
+func (this result) RowsAffected() (int64, error) {
+	return this.rowsAffected, nil
+}
+
+type rows struct {
+	columns []string
+	values  [][]driver.Value
+	pos     int
+}
+
+func (this *rows) Columns() []string {
+	return this.columns
+}
+
+func (this *rows) Close() error {
+	return nil
+}
+
+func (this *rows) Next(dest []driver.Value) error {
+	if this.pos >= len(this.values) {
+		return io.EOF
+	}
+	copy(dest, this.values[this.pos])
+	this.pos++
+	return nil
+}
+
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
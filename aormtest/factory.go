@@ -0,0 +1,381 @@
+package aormtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+	"github.com/moisespsena-go/aorm/types"
+)
+
+var (
+	factories   = map[reflect.Type]*Factory{}
+	factoriesMu sync.RWMutex
+
+	emailType = reflect.TypeOf(types.Email(""))
+	moneyType = reflect.TypeOf(types.Money(0))
+	timeType  = reflect.TypeOf(time.Time{})
+)
+
+// Attrs overrides the attributes of built records, by field name
+type Attrs map[string]interface{}
+
+// Factory builds records of model. Fields not set by factory receive default values derived from its type and
+// tag settings. Belongs to parents with zero foreign keys, or declared by Association, are built, or created,
+// with the factory of parent model.
+//
+//	aormtest.Define(&User{}, func(f *aormtest.Factory) {
+//		f.Sequence("Name", "user-%d")
+//		f.Trait("admin", func(f *aormtest.Factory) {
+//			f.Set("Admin", true)
+//		})
+//	})
+//
+//	var user User
+//	aormtest.Create(db, &user, "admin", aormtest.Attrs{"Name": "alice"})
+type Factory struct {
+	model *aorm.ModelStruct
+	seq   int64
+
+	attrs        []factoryAttr
+	traits       map[string]func(f *Factory)
+	associations map[string][]interface{}
+	afterBuild   []func(record interface{})
+}
+
+type factoryAttr struct {
+	name  string
+	value func(seq int) interface{}
+}
+
+// Define defines the factory of model
+func Define(model interface{}, define func(f *Factory)) *Factory {
+	f := newFactory(aorm.StructOf(model))
+	if define != nil {
+		define(f)
+	}
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	factories[f.model.Type] = f
+	return f
+}
+
+// FactoryOf returns the factory of model, or a factory without definitions if model is not defined
+func FactoryOf(model interface{}) *Factory {
+	ms := aorm.StructOf(model)
+	factoriesMu.RLock()
+	f := factories[ms.Type]
+	factoriesMu.RUnlock()
+	if f != nil {
+		return f
+	}
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+	if f = factories[ms.Type]; f == nil {
+		f = newFactory(ms)
+		factories[ms.Type] = f
+	}
+	return f
+}
+
+func newFactory(ms *aorm.ModelStruct) *Factory {
+	return &Factory{model: ms, traits: map[string]func(f *Factory){}, associations: map[string][]interface{}{}}
+}
+
+// Set sets the field value
+func (this *Factory) Set(field string, value interface{}) *Factory {
+	return this.Func(field, func(int) interface{} {
+		return value
+	})
+}
+
+// Func sets the field value returned by fn, with the sequence number of built record
+func (this *Factory) Func(field string, fn func(seq int) interface{}) *Factory {
+	this.attrs = append(this.attrs, factoryAttr{field, fn})
+	return this
+}
+
+// Sequence sets the field value formatted with the sequence number of built record
+func (this *Factory) Sequence(field, format string) *Factory {
+	return this.Func(field, func(seq int) interface{} {
+		return fmt.Sprintf(format, seq)
+	})
+}
+
+// Trait defines the named trait, applied when building with the trait name
+func (this *Factory) Trait(name string, define func(f *Factory)) *Factory {
+	this.traits[name] = define
+	return this
+}
+
+// Association declares the belongs to field, built with the factory of field model, using the traits and Attrs
+// of opts
+func (this *Factory) Association(field string, opts ...interface{}) *Factory {
+	this.associations[field] = opts
+	return this
+}
+
+// AfterBuild adds callback called after build each record
+func (this *Factory) AfterBuild(f func(record interface{})) *Factory {
+	this.afterBuild = append(this.afterBuild, f)
+	return this
+}
+
+// Build builds the record into dest, without database, using the traits (strings) and Attrs of opts
+func Build(dest interface{}, opts ...interface{}) error {
+	return FactoryOf(dest).build(nil, dest, opts)
+}
+
+// Create builds the record into dest and creates it with its belongs to parents, using the traits (strings)
+// and Attrs of opts
+func Create(db *aorm.DB, dest interface{}, opts ...interface{}) error {
+	return FactoryOf(dest).build(db, dest, opts)
+}
+
+// BuildList builds n records into the slice pointed by dest
+func BuildList(dest interface{}, n int, opts ...interface{}) error {
+	return buildList(nil, dest, n, opts)
+}
+
+// CreateList creates n records into the slice pointed by dest
+func CreateList(db *aorm.DB, dest interface{}, n int, opts ...interface{}) error {
+	return buildList(db, dest, n, opts)
+}
+
+func buildList(db *aorm.DB, dest interface{}, n int, opts []interface{}) error {
+	slice := reflect.ValueOf(dest)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return errors.Errorf("aormtest: %T is not a slice pointer", dest)
+	}
+	slice = slice.Elem()
+	var (
+		elemType = slice.Type().Elem()
+		isPtr    = elemType.Kind() == reflect.Ptr
+	)
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	f := FactoryOf(reflect.New(elemType).Interface())
+	for i := 0; i < n; i++ {
+		record := reflect.New(elemType)
+		if err := f.build(db, record.Interface(), opts); err != nil {
+			return err
+		}
+		if isPtr {
+			slice.Set(reflect.Append(slice, record))
+		} else {
+			slice.Set(reflect.Append(slice, record.Elem()))
+		}
+	}
+	return nil
+}
+
+// resolve returns the definition of factory with traits applied
+func (this *Factory) resolve(opts []interface{}) (f *Factory, attrs Attrs, err error) {
+	f = &Factory{
+		model:        this.model,
+		attrs:        append([]factoryAttr{}, this.attrs...),
+		traits:       this.traits,
+		associations: map[string][]interface{}{},
+		afterBuild:   append([]func(record interface{}){}, this.afterBuild...),
+	}
+	for name, opts := range this.associations {
+		f.associations[name] = opts
+	}
+	attrs = Attrs{}
+	for _, opt := range opts {
+		switch t := opt.(type) {
+		case string:
+			trait, ok := this.traits[t]
+			if !ok {
+				return nil, nil, errors.Errorf("aormtest: trait %q of %s does not exists", t, this.model.Type)
+			}
+			trait(f)
+		case Attrs:
+			for name, value := range t {
+				attrs[name] = value
+			}
+		case map[string]interface{}:
+			for name, value := range t {
+				attrs[name] = value
+			}
+		default:
+			return nil, nil, errors.Errorf("aormtest: bad factory option %T", opt)
+		}
+	}
+	return
+}
+
+func (this *Factory) build(db *aorm.DB, dest interface{}, opts []interface{}) (err error) {
+	var (
+		seq   = int(atomic.AddInt64(&this.seq, 1))
+		value = reflect.ValueOf(dest)
+		f     *Factory
+		attrs Attrs
+		set   = map[string]bool{}
+	)
+	if value.Kind() != reflect.Ptr || value.Elem().Type() != this.model.Type {
+		return errors.Errorf("aormtest: dest must be pointer of %s, but got %T", this.model.Type, dest)
+	}
+	if f, attrs, err = this.resolve(opts); err != nil {
+		return
+	}
+	elem := value.Elem()
+
+	setField := func(name string, v interface{}) error {
+		field, ok := this.model.FieldByName(name)
+		if !ok {
+			return errors.Errorf("aormtest: field %s.%s does not exists", this.model.Type, name)
+		}
+		set[field.Name] = true
+		return errors.Wrapf(assign(elem.FieldByIndex(field.StructIndex), v), "aormtest: field %s.%s", this.model.Type, name)
+	}
+
+	for _, attr := range f.attrs {
+		if _, ok := attrs[attr.name]; ok {
+			continue
+		}
+		if err = setField(attr.name, attr.value(seq)); err != nil {
+			return
+		}
+	}
+	for name, v := range attrs {
+		if err = setField(name, v); err != nil {
+			return
+		}
+	}
+
+	if err = f.buildParents(db, elem, set); err != nil {
+		return
+	}
+
+	for _, field := range this.model.Fields {
+		if !set[field.Name] {
+			defaultValue(field, elem.FieldByIndex(field.StructIndex), seq)
+		}
+	}
+
+	for _, cb := range f.afterBuild {
+		cb(dest)
+	}
+
+	if db != nil {
+		err = db.Set("aorm:save_associations", false).Create(dest).Error
+	}
+	return
+}
+
+// buildParents builds, or creates if db isn't nil, the declared and required belongs to parents, and sets the
+// foreign keys of parents
+func (this *Factory) buildParents(db *aorm.DB, elem reflect.Value, set map[string]bool) (err error) {
+	for _, field := range this.model.Fields {
+		rel := field.Relationship
+		if rel == nil || rel.Kind != "belongs_to" {
+			continue
+		}
+		var (
+			fieldValue = elem.FieldByIndex(field.StructIndex)
+			parent     reflect.Value
+		)
+
+		if set[field.Name] {
+			// parent set by attributes
+			if parent = reflect.Indirect(fieldValue); !parent.IsValid() {
+				continue
+			}
+		} else {
+			var (
+				opts, declared = this.associations[field.Name]
+				required       = true
+			)
+			for _, name := range rel.ForeignFieldNames {
+				fk, _ := this.model.FieldByName(name)
+				if set[fk.Name] || !elem.FieldByIndex(fk.StructIndex).IsZero() {
+					// foreign key set by attributes
+					required, declared = false, false
+					break
+				}
+				if fk.Struct.Type.Kind() == reflect.Ptr || fk.IsPrimaryKey {
+					required = false
+				}
+			}
+			if !required && !declared {
+				continue
+			}
+
+			parentType := field.Struct.Type
+			if parentType.Kind() == reflect.Ptr {
+				parentType = parentType.Elem()
+			}
+			ptr := reflect.New(parentType)
+			if err = FactoryOf(ptr.Interface()).build(db, ptr.Interface(), opts); err != nil {
+				return errors.Wrapf(err, "aormtest: parent %s.%s", this.model.Type, field.Name)
+			}
+			if fieldValue.Kind() == reflect.Ptr {
+				fieldValue.Set(ptr)
+			} else {
+				fieldValue.Set(ptr.Elem())
+			}
+			parent = ptr.Elem()
+			set[field.Name] = true
+		}
+
+		for i, name := range rel.ForeignFieldNames {
+			fk, _ := this.model.FieldByName(name)
+			pk, _ := rel.AssociationModel.FieldByName(rel.AssociationForeignFieldNames[i])
+			if err = assign(elem.FieldByIndex(fk.StructIndex), parent.FieldByIndex(pk.StructIndex).Interface()); err != nil {
+				return
+			}
+			set[fk.Name] = true
+		}
+	}
+	return
+}
+
+// defaultValue sets default value of zero field, derived from its type and tag settings
+func defaultValue(field *aorm.StructField, value reflect.Value, seq int) {
+	if !field.IsNormal || field.IsPrimaryKey || field.IsForeignKey || field.IsReadOnly || !value.IsZero() ||
+		value.Kind() == reflect.Ptr || field.HasDefaultValue || field.Name == aorm.TenantFieldTenantID {
+		return
+	}
+	switch field.Name {
+	case "CreatedAt", "UpdatedAt", "DeletedAt":
+		return
+	}
+
+	switch typ := value.Type(); {
+	case typ == emailType:
+		value.SetString(fmt.Sprintf("%s%d@example.com", strings.ToLower(field.DBName), seq))
+	case typ == moneyType:
+		value.SetFloat(float64(seq) + 0.99)
+	case typ == timeType:
+		value.Set(reflect.ValueOf(time.Now().Truncate(time.Second)))
+	default:
+		switch value.Kind() {
+		case reflect.String:
+			s := fmt.Sprintf("%s-%d", field.Name, seq)
+			if size := field.TextSize(); size > 0 && len(s) > size {
+				s = s[len(s)-size:]
+			}
+			value.SetString(s)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if value.OverflowInt(int64(seq)) {
+				seq = seq % 127
+			}
+			value.SetInt(int64(seq))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if value.OverflowUint(uint64(seq)) {
+				seq = seq % 255
+			}
+			value.SetUint(uint64(seq))
+		case reflect.Float32, reflect.Float64:
+			value.SetFloat(float64(seq))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package aormtest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/aorm/aormtest"
+	"github.com/moisespsena-go/aorm/types"
+)
+
+type FactoryUser struct {
+	ID      int
+	Name    string `sql:"size:8"`
+	Email   types.Email
+	Balance types.Money
+	Admin   bool
+}
+
+func (FactoryUser) TableName() string {
+	return "factory_users"
+}
+
+type FactoryPost struct {
+	ID       int
+	Title    string
+	AuthorID int
+	Author   *FactoryUser
+}
+
+func (FactoryPost) TableName() string {
+	return "factory_posts"
+}
+
+func init() {
+	aormtest.Define(&FactoryUser{}, func(f *aormtest.Factory) {
+		f.Sequence("Name", "user-%d")
+		f.Trait("admin", func(f *aormtest.Factory) {
+			f.Set("Admin", true)
+		})
+	})
+}
+
+func TestFactoryBuild(t *testing.T) {
+	var user, admin FactoryUser
+	if err := aormtest.Build(&user); err != nil {
+		t.Fatalf("no error should happen when build, but got %v", err)
+	}
+	if !strings.HasPrefix(user.Name, "user-") || user.Admin {
+		t.Errorf("user should be built from factory definition, but got %+v", user)
+	}
+	if !strings.HasSuffix(string(user.Email), "@example.com") || user.Balance == 0 {
+		t.Errorf("user should have default email and balance, but got %+v", user)
+	}
+
+	if err := aormtest.Build(&admin, "admin", aormtest.Attrs{"Name": "root"}); err != nil {
+		t.Fatalf("no error should happen when build, but got %v", err)
+	}
+	if admin.Name != "root" || !admin.Admin {
+		t.Errorf("admin should be built with trait and attributes, but got %+v", admin)
+	}
+	if admin.Email == user.Email {
+		t.Errorf("sequences should generate distinct values, but got %q twice", admin.Email)
+	}
+
+	if err := aormtest.Build(&user, "unknown"); err == nil {
+		t.Errorf("unknown trait should fail")
+	}
+}
+
+func TestFactoryCreate(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+	mock.ExpectRegexp(`^INSERT INTO "factory_users"`).WillReturnResult(7, 1)
+	mock.ExpectRegexp(`^INSERT INTO "factory_posts"`).WillReturnResult(3, 1)
+
+	var post FactoryPost
+	if err := aormtest.Create(db, &post); err != nil {
+		t.Fatalf("no error should happen when create, but got %v", err)
+	}
+	if post.ID != 3 || post.Author == nil || post.Author.ID != 7 || post.AuthorID != 7 {
+		t.Errorf("post should be created with its author, but got %+v", post)
+	}
+
+	var posts []*FactoryPost
+	if err := aormtest.BuildList(&posts, 3, aormtest.Attrs{"AuthorID": 7}); err != nil {
+		t.Fatalf("no error should happen when build list, but got %v", err)
+	}
+	if len(posts) != 3 || posts[0].Author != nil || posts[2].AuthorID != 7 {
+		t.Errorf("posts should be built without authors, but got %v", posts)
+	}
+}
@@ -0,0 +1,413 @@
+package aormtest
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+// Fixtures is the set of records loaded from fixture files.
+//
+// Each file of fixtures directory, named as `<table>.yml`, `<table>.yaml` or `<table>.json`, is the map of
+// record label to record attributes. Attributes are field names or column names. Values of form `$table.label`
+// are references to other records: on belongs to fields sets the foreign keys, on many to many fields (a list
+// of references) adds join table rows, and on other fields sets the primary key of referenced record. Use `$$`
+// to escape a leading `$`.
+//
+//	# users.yml
+//	alice:
+//	  name: Alice
+//	# posts.yml
+//	hello:
+//	  title: Hello
+//	  author: $users.alice
+//	  tags: [$tags.go, $tags.sql]
+//
+// Records are inserted in dependency order of references.
+type Fixtures struct {
+	db      *aorm.DB
+	tables  map[string]*fixtureTable
+	order   []*fixtureRecord
+	records map[string]map[string]*fixtureRecord
+}
+
+type fixtureTable struct {
+	name  string
+	model *aorm.ModelStruct
+}
+
+type fixtureRecord struct {
+	table *fixtureTable
+	label string
+	attrs map[string]interface{}
+	value interface{}
+}
+
+func (this *fixtureRecord) String() string {
+	return this.table.name + "." + this.label
+}
+
+// LoadFixtures reads the fixture files of dir and inserts its records. The table of each file is resolved from
+// models, or from all model structs known by db if models is empty.
+func LoadFixtures(db *aorm.DB, dir string, models ...interface{}) (fixtures *Fixtures, err error) {
+	if fixtures, err = ReadFixtures(db, dir, models...); err != nil {
+		return
+	}
+	if err = fixtures.Insert(); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ReadFixtures reads the fixture files of dir, without insert the records
+func ReadFixtures(db *aorm.DB, dir string, models ...interface{}) (fixtures *Fixtures, err error) {
+	fixtures = &Fixtures{
+		db:      db,
+		tables:  map[string]*fixtureTable{},
+		records: map[string]map[string]*fixtureRecord{},
+	}
+
+	var modelStructs []*aorm.ModelStruct
+	if len(models) == 0 {
+		modelStructs = db.ModelStructStorage().ModelStructsMap.Values()
+	} else {
+		for _, model := range models {
+			modelStructs = append(modelStructs, db.StructOf(model))
+		}
+	}
+	for _, ms := range modelStructs {
+		name := db.NewModelScope(ms, ms.Value).TableName()
+		fixtures.tables[name] = &fixtureTable{name, ms}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "aormtest: read fixtures")
+	}
+
+	for _, file := range files {
+		ext := filepath.Ext(file.Name())
+		if file.IsDir() || (ext != ".yml" && ext != ".yaml" && ext != ".json") {
+			continue
+		}
+		var (
+			name  = strings.TrimSuffix(file.Name(), ext)
+			table = fixtures.tables[name]
+			data  []byte
+			attrs map[string]map[string]interface{}
+		)
+		if table == nil {
+			return nil, errors.Errorf("aormtest: fixture %q: model of table %q is not registered", file.Name(), name)
+		}
+		if data, err = ioutil.ReadFile(filepath.Join(dir, file.Name())); err != nil {
+			return nil, errors.Wrapf(err, "aormtest: fixture %q", file.Name())
+		}
+		if ext == ".json" {
+			err = json.Unmarshal(data, &attrs)
+		} else {
+			err = yaml.Unmarshal(data, &attrs)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "aormtest: fixture %q", file.Name())
+		}
+		records := map[string]*fixtureRecord{}
+		for label, attrs := range attrs {
+			records[label] = &fixtureRecord{table: table, label: label, attrs: attrs}
+		}
+		fixtures.records[name] = records
+	}
+	return
+}
+
+// Get returns the record of table with label, or nil if it is not loaded
+func (this *Fixtures) Get(table, label string) interface{} {
+	if record := this.records[table][label]; record != nil {
+		return record.value
+	}
+	return nil
+}
+
+// MustGet returns the record of table with label, panicking if it is not loaded
+func (this *Fixtures) MustGet(table, label string) interface{} {
+	if value := this.Get(table, label); value != nil {
+		return value
+	}
+	panic(fmt.Sprintf("aormtest: fixture %s.%s is not loaded", table, label))
+}
+
+// Insert inserts the records in dependency order, and the join table rows of many to many references
+func (this *Fixtures) Insert() (err error) {
+	if err = this.sort(); err != nil {
+		return
+	}
+
+	var joins []func() error
+	for _, record := range this.order {
+		var recordJoins []func() error
+		if recordJoins, err = this.insert(record); err != nil {
+			return errors.Wrapf(err, "aormtest: fixture %s", record)
+		}
+		joins = append(joins, recordJoins...)
+	}
+	for _, join := range joins {
+		if err = join(); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Truncate deletes all rows of fixture tables, and its join tables
+func (this *Fixtures) Truncate() error {
+	var (
+		tables []string
+		seen   = map[string]bool{}
+		add    = func(table string) {
+			if !seen[table] {
+				seen[table] = true
+				tables = append(tables, table)
+			}
+		}
+	)
+
+	// join tables, then fixture tables in reverse dependency order
+	for name := range this.records {
+		for _, field := range this.tables[name].model.Fields {
+			if rel := field.Relationship; rel != nil && rel.JoinTableHandler != nil {
+				add(rel.JoinTableHandler.Table(this.db))
+			}
+		}
+	}
+	for i := len(this.order) - 1; i >= 0; i-- {
+		add(this.order[i].table.name)
+	}
+	for name := range this.records {
+		add(name)
+	}
+
+	for _, table := range tables {
+		if err := Truncate(this.db, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload truncates the tables and inserts the records again
+func (this *Fixtures) Reload() error {
+	if err := this.Truncate(); err != nil {
+		return err
+	}
+	return this.Insert()
+}
+
+// Truncate deletes all rows of table, restarting the identity sequences on postgres
+func Truncate(db *aorm.DB, table string) error {
+	if schema := db.Schema(); schema != "" && !strings.Contains(table, ".") {
+		table = schema + "." + table
+	}
+	var query string
+	if db.Dialect().GetName() == "postgres" {
+		query = fmt.Sprintf("TRUNCATE TABLE %v RESTART IDENTITY CASCADE", aorm.QuotePath(db.Dialect(), table))
+	} else {
+		query = fmt.Sprintf("DELETE FROM %v", aorm.QuotePath(db.Dialect(), table))
+	}
+	return errors.Wrapf(db.Exec(query).Error, "aormtest: truncate %q", table)
+}
+
+// sort sorts the records by dependency order of references
+func (this *Fixtures) sort() (err error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	var (
+		state = map[*fixtureRecord]int{}
+		visit func(record *fixtureRecord) error
+	)
+	this.order = nil
+
+	visit = func(record *fixtureRecord) (err error) {
+		switch state[record] {
+		case visited:
+			return
+		case visiting:
+			return errors.Errorf("aormtest: fixture %s has cyclic references", record)
+		}
+		state[record] = visiting
+		for _, key := range sortedKeys(record.attrs) {
+			field, ok := record.table.model.FieldByName(key)
+			if !ok {
+				return errors.Errorf("aormtest: fixture %s: field %q does not exists", record, key)
+			}
+			if rel := field.Relationship; rel != nil && rel.Kind != "belongs_to" {
+				// many to many references are inserted after all records
+				continue
+			}
+			if ref, isRef, err := this.ref(record.attrs[key]); err != nil {
+				return errors.Wrapf(err, "aormtest: fixture %s: field %q", record, key)
+			} else if isRef {
+				if err = visit(ref); err != nil {
+					return err
+				}
+			}
+		}
+		state[record] = visited
+		this.order = append(this.order, record)
+		return
+	}
+
+	for _, name := range sortedKeys(this.records) {
+		for _, label := range sortedKeys(this.records[name]) {
+			if err = visit(this.records[name][label]); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// ref returns the record referenced by value
+func (this *Fixtures) ref(value interface{}) (record *fixtureRecord, ok bool, err error) {
+	s, isString := value.(string)
+	if !isString || !strings.HasPrefix(s, "$") || strings.HasPrefix(s, "$$") {
+		return
+	}
+	parts := strings.SplitN(s[1:], ".", 2)
+	if len(parts) != 2 {
+		return nil, false, errors.Errorf("bad reference %q", s)
+	}
+	if record = this.records[parts[0]][parts[1]]; record == nil {
+		return nil, false, errors.Errorf("reference %q does not exists", s)
+	}
+	return record, true, nil
+}
+
+func (this *Fixtures) insert(record *fixtureRecord) (joins []func() error, err error) {
+	var (
+		ms    = record.table.model
+		value = reflect.New(ms.Type)
+		elem  = value.Elem()
+	)
+
+	for _, key := range sortedKeys(record.attrs) {
+		var (
+			field, _ = ms.FieldByName(key)
+			attr     = record.attrs[key]
+			ref      *fixtureRecord
+			isRef    bool
+		)
+		if ref, isRef, err = this.ref(attr); err != nil {
+			return
+		}
+
+		if rel := field.Relationship; rel != nil {
+			switch {
+			case rel.Kind == "belongs_to" && isRef:
+				refValue := reflect.Indirect(reflect.ValueOf(ref.value))
+				for i, name := range rel.ForeignFieldNames {
+					fk, _ := ms.FieldByName(name)
+					src, _ := ref.table.model.FieldByName(rel.AssociationForeignFieldNames[i])
+					elem.FieldByIndex(fk.StructIndex).Set(refValue.FieldByIndex(src.StructIndex))
+				}
+			case rel.JoinTableHandler != nil:
+				refs, ok := attr.([]interface{})
+				if !ok {
+					return nil, errors.Errorf("field %q: many to many value must be a list of references", key)
+				}
+				for _, r := range refs {
+					var dst *fixtureRecord
+					if dst, isRef, err = this.ref(r); err != nil {
+						return
+					} else if !isRef {
+						return nil, errors.Errorf("field %q: %v is not a reference", key, r)
+					}
+					handler := rel.JoinTableHandler
+					joins = append(joins, func() error {
+						return errors.Wrapf(handler.Add(handler, this.db, value.Interface(), dst.value),
+							"aormtest: fixture %s: join %s", record, dst)
+					})
+				}
+			default:
+				return nil, errors.Errorf("field %q: %s relationship must be defined by the other side", key, rel.Kind)
+			}
+			continue
+		}
+
+		fieldValue := elem.FieldByIndex(field.StructIndex)
+		if isRef {
+			refPK := ref.table.model.PrimaryField()
+			attr = reflect.Indirect(reflect.ValueOf(ref.value)).FieldByIndex(refPK.StructIndex).Interface()
+		} else if s, ok := attr.(string); ok && strings.HasPrefix(s, "$$") {
+			attr = s[1:]
+		}
+		if err = assign(fieldValue, attr); err != nil {
+			return nil, errors.Wrapf(err, "field %q", key)
+		}
+	}
+
+	if err = this.db.Create(value.Interface()).Error; err != nil {
+		return
+	}
+	record.value = value.Interface()
+	return
+}
+
+// assign sets value to field, converting it if possible, or scanning it, or decoding it as JSON
+func assign(field reflect.Value, value interface{}) (err error) {
+	if value == nil {
+		return
+	}
+	v := reflect.ValueOf(value)
+	if field.Kind() == reflect.Ptr {
+		if v.Type().AssignableTo(field.Type()) {
+			field.Set(v)
+			return
+		}
+		ptr := reflect.New(field.Type().Elem())
+		if err = assign(ptr.Elem(), value); err == nil {
+			field.Set(ptr)
+		}
+		return
+	}
+	if v.Type().AssignableTo(field.Type()) {
+		field.Set(v)
+		return
+	}
+	if v.Type().ConvertibleTo(field.Type()) && v.Kind() != reflect.String && field.Kind() != reflect.String {
+		field.Set(v.Convert(field.Type()))
+		return
+	}
+	if v.Kind() == reflect.String && field.Kind() == reflect.String {
+		field.SetString(v.String())
+		return
+	}
+	if scanner, ok := field.Addr().Interface().(sql.Scanner); ok {
+		if err = scanner.Scan(value); err == nil {
+			return
+		}
+	}
+	var data []byte
+	if data, err = json.Marshal(value); err != nil {
+		return
+	}
+	return json.Unmarshal(data, field.Addr().Interface())
+}
+
+func sortedKeys(m interface{}) (keys []string) {
+	for _, key := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return
+}
@@ -0,0 +1,79 @@
+package aormtest_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/aorm/aormtest"
+)
+
+type FixtureUser struct {
+	ID   int
+	Name string
+}
+
+func (FixtureUser) TableName() string {
+	return "fixture_users"
+}
+
+type FixtureTag struct {
+	ID   int
+	Name string
+}
+
+func (FixtureTag) TableName() string {
+	return "fixture_tags"
+}
+
+type FixturePost struct {
+	ID       int
+	Title    string
+	AuthorID int
+	Author   *FixtureUser
+	ParentID int
+	Tags     []FixtureTag `aorm:"many2many:fixture_post_tags"`
+}
+
+func (FixturePost) TableName() string {
+	return "fixture_posts"
+}
+
+func TestLoadFixtures(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+
+	fixtures, err := aormtest.LoadFixtures(db, "testdata/fixtures", &FixtureUser{}, &FixtureTag{}, &FixturePost{})
+	if err != nil {
+		t.Fatalf("no error should happen when load fixtures, but got %v", err)
+	}
+
+	var inserts []string
+	for _, stmt := range mock.Statements() {
+		if strings.HasPrefix(stmt.SQL, "INSERT INTO") {
+			inserts = append(inserts, strings.Trim(strings.Fields(stmt.SQL)[2], `"`))
+		}
+	}
+	expected := []string{
+		"fixture_users", "fixture_posts", "fixture_users", "fixture_posts", "fixture_tags", "fixture_tags",
+		"fixture_post_tags", "fixture_post_tags",
+	}
+	if !reflect.DeepEqual(inserts, expected) {
+		t.Errorf("should insert records in dependency order, then join rows, but got %v", inserts)
+	}
+
+	reply := fixtures.MustGet("fixture_posts", "reply").(*FixturePost)
+	if reply.AuthorID != 2 || reply.ParentID != 10 {
+		t.Errorf("references should be resolved, but got %+v", reply)
+	}
+	if bob := fixtures.MustGet("fixture_users", "bob").(*FixtureUser); bob.Name != "$bob" {
+		t.Errorf("escaped value should be %q, but got %q", "$bob", bob.Name)
+	}
+
+	mock.Reset()
+	if err = fixtures.Truncate(); err != nil {
+		t.Fatalf("no error should happen when truncate, but got %v", err)
+	}
+	if statements := mock.Statements(); len(statements) != 4 || statements[0].SQL != `DELETE FROM "fixture_post_tags"` {
+		t.Errorf("should delete join table then fixture tables, but got %v", statements)
+	}
+}
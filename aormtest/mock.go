@@ -0,0 +1,326 @@
+// Package aormtest provides an in-memory database connection for unit tests of code using aorm, without a
+// database server. The connection records every statement, and returns the canned results of expectations
+// registered by the test.
+//
+//	func TestActivate(t *testing.T) {
+//		db, mock := aormtest.New(t, "postgres")
+//		mock.ExpectRegexp(`^UPDATE "users" SET "active" = \$1`).WithArgs(true, 10).WillReturnResult(0, 1)
+//		if err := Activate(db, 10); err != nil {
+//			t.Fatal(err)
+//		}
+//	}
+package aormtest
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+// AnyArg matches any argument value
+var AnyArg = anyArg{}
+
+type anyArg struct{}
+
+// TB is the subset of testing.TB used by Mock
+type TB interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+	Cleanup(func())
+}
+
+// Statement is the statement executed by the connection
+type Statement struct {
+	SQL  string
+	Args []interface{}
+	// Query is true if the statement was executed as query, returning rows
+	Query bool
+}
+
+func (this Statement) String() string {
+	if len(this.Args) == 0 {
+		return this.SQL
+	}
+	return fmt.Sprintf("%s %v", this.SQL, this.Args)
+}
+
+// Mock is the in-memory database of connection opened by New or Open
+type Mock struct {
+	// Strict makes statements that do not match any expectation to fail
+	Strict bool
+
+	dsn          string
+	mu           sync.Mutex
+	statements   []Statement
+	expectations []*Expectation
+}
+
+// New opens the aorm connection of dialect with a new Mock, and asserts the expectations of mock at the
+// end of test.
+func New(t TB, dialect string) (*aorm.DB, *Mock) {
+	t.Helper()
+	db, mock, err := Open(dialect)
+	if err != nil {
+		t.Fatalf("aormtest: open: %v", err)
+	}
+	t.Cleanup(func() {
+		t.Helper()
+		mock.AssertExpectations(t)
+		db.Close()
+		mocks.Delete(mock.dsn)
+	})
+	return db, mock
+}
+
+// Open opens the aorm connection of dialect with a new Mock. Statements executed by the dialect while opening
+// the connection are not recorded.
+func Open(dialect string) (db *aorm.DB, mock *Mock, err error) {
+	mock = &Mock{}
+	var sqlDB *sql.DB
+	if sqlDB, err = sql.Open(DriverName, registerMock(mock)); err != nil {
+		return
+	}
+	if db, err = aorm.Open(dialect, sqlDB); err != nil {
+		sqlDB.Close()
+		return
+	}
+	mock.Reset()
+	return
+}
+
+// Expect registers the expectation of statement with sql, compared ignoring repeated spaces
+func (this *Mock) Expect(sql string) *Expectation {
+	return this.expect(&Expectation{sql: normalize(sql)})
+}
+
+// ExpectRegexp registers the expectation of statements matching the regular expression
+func (this *Mock) ExpectRegexp(pattern string) *Expectation {
+	return this.expect(&Expectation{re: regexp.MustCompile(pattern)})
+}
+
+func (this *Mock) expect(e *Expectation) *Expectation {
+	e.mock, e.times = this, 1
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.expectations = append(this.expectations, e)
+	return e
+}
+
+// Statements returns the executed statements
+func (this *Mock) Statements() []Statement {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return append([]Statement{}, this.statements...)
+}
+
+// Reset clears the executed statements and the expectations
+func (this *Mock) Reset() {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.statements, this.expectations = nil, nil
+}
+
+// Unmet returns the expectations that were not called the expected times
+func (this *Mock) Unmet() (unmet []*Expectation) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	for _, e := range this.expectations {
+		if !e.met() {
+			unmet = append(unmet, e)
+		}
+	}
+	return
+}
+
+// AssertExpectations reports the unmet expectations as test errors
+func (this *Mock) AssertExpectations(t interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}) bool {
+	t.Helper()
+	unmet := this.Unmet()
+	for _, e := range unmet {
+		t.Errorf("aormtest: unmet expectation %v", e)
+	}
+	return len(unmet) == 0
+}
+
+// handle records the statement and returns the matched expectation. Transaction statements (BEGIN, COMMIT and
+// ROLLBACK) are recorded too, but they are not failed by Strict.
+func (this *Mock) handle(query string, args []driver.NamedValue, isQuery bool) (*Expectation, error) {
+	stmt := Statement{SQL: query, Query: isQuery}
+	for _, arg := range args {
+		stmt.Args = append(stmt.Args, arg.Value)
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.statements = append(this.statements, stmt)
+
+	for _, e := range this.expectations {
+		if e.exhausted() || !e.match(stmt) {
+			continue
+		}
+		e.called++
+		return e, e.err
+	}
+	if this.Strict && !isTxStatement(query) {
+		return nil, fmt.Errorf("aormtest: unexpected statement %v", stmt)
+	}
+	return nil, nil
+}
+
+// Expectation is the expected statement and its canned result
+type Expectation struct {
+	mock   *Mock
+	sql    string
+	re     *regexp.Regexp
+	args   []interface{}
+	hasArg bool
+
+	columns      []string
+	rows         [][]driver.Value
+	lastInsertId int64
+	rowsAffected int64
+	err          error
+
+	times  int
+	called int
+}
+
+// WithArgs restricts the expectation to statements with args. Use AnyArg to match any value.
+func (this *Expectation) WithArgs(args ...interface{}) *Expectation {
+	this.hasArg = true
+	this.args = nil
+	for _, arg := range args {
+		if arg != AnyArg {
+			if v, err := driver.DefaultParameterConverter.ConvertValue(arg); err == nil {
+				arg = v
+			}
+		}
+		this.args = append(this.args, arg)
+	}
+	return this
+}
+
+// WillReturnRows sets the rows returned by query
+func (this *Expectation) WillReturnRows(rows *Rows) *Expectation {
+	this.columns, this.rows = rows.columns, rows.values
+	return this
+}
+
+// WillReturnResult sets the result of exec
+func (this *Expectation) WillReturnResult(lastInsertId, rowsAffected int64) *Expectation {
+	this.lastInsertId, this.rowsAffected = lastInsertId, rowsAffected
+	return this
+}
+
+// WillReturnError sets the error of statement
+func (this *Expectation) WillReturnError(err error) *Expectation {
+	this.err = err
+	return this
+}
+
+// Times sets the number of times the statement is expected. Defaults to 1.
+func (this *Expectation) Times(n int) *Expectation {
+	this.times = n
+	return this
+}
+
+// AnyTimes expects the statement zero or more times
+func (this *Expectation) AnyTimes() *Expectation {
+	return this.Times(-1)
+}
+
+// Called returns the number of times the statement was executed
+func (this *Expectation) Called() int {
+	this.mock.mu.Lock()
+	defer this.mock.mu.Unlock()
+	return this.called
+}
+
+func (this *Expectation) met() bool {
+	return this.times < 0 || this.called >= this.times
+}
+
+func (this *Expectation) exhausted() bool {
+	return this.times >= 0 && this.called >= this.times
+}
+
+func (this *Expectation) match(stmt Statement) bool {
+	if this.re != nil {
+		if !this.re.MatchString(stmt.SQL) {
+			return false
+		}
+	} else if this.sql != normalize(stmt.SQL) {
+		return false
+	}
+	if !this.hasArg {
+		return true
+	}
+	if len(this.args) != len(stmt.Args) {
+		return false
+	}
+	for i, arg := range this.args {
+		if arg != AnyArg && !reflect.DeepEqual(arg, stmt.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (this *Expectation) String() string {
+	var s string
+	if this.re != nil {
+		s = "regexp " + this.re.String()
+	} else {
+		s = this.sql
+	}
+	if this.hasArg {
+		s += fmt.Sprintf(" %v", this.args)
+	}
+	return fmt.Sprintf("%s (called %d of %d times)", s, this.called, this.times)
+}
+
+// Rows is the canned rows of query
+type Rows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+// NewRows creates rows with columns
+func NewRows(columns ...string) *Rows {
+	return &Rows{columns: columns}
+}
+
+// AddRow adds the row with values of each column
+func (this *Rows) AddRow(values ...interface{}) *Rows {
+	if len(values) != len(this.columns) {
+		panic(fmt.Sprintf("aormtest: row has %d values, but expected %d columns", len(values), len(this.columns)))
+	}
+	row := make([]driver.Value, len(values))
+	for i, value := range values {
+		v, err := driver.DefaultParameterConverter.ConvertValue(value)
+		if err != nil {
+			panic(fmt.Sprintf("aormtest: invalid value of column %q: %v", this.columns[i], err))
+		}
+		row[i] = v
+	}
+	this.values = append(this.values, row)
+	return this
+}
+
+func isTxStatement(query string) bool {
+	return strings.HasPrefix(query, "BEGIN") || query == "COMMIT" || query == "ROLLBACK"
+}
+
+func normalize(sql string) string {
+	return strings.Join(strings.Fields(sql), " ")
+}
@@ -0,0 +1,85 @@
+package aormtest_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/aorm/aormtest"
+)
+
+type Account struct {
+	ID     int
+	Name   string
+	Active bool
+}
+
+func TestMock(t *testing.T) {
+	db, mock := aormtest.New(t, "postgres")
+
+	mock.ExpectRegexp(`^SELECT .* FROM "aormtest_test_accounts" +WHERE`).
+		WillReturnRows(aormtest.NewRows("id", "name", "active").AddRow(1, "alice", true))
+	mock.ExpectRegexp(`^UPDATE "aormtest_test_accounts" SET "active" = \$1`).
+		WithArgs(false, 1).
+		WillReturnResult(0, 1)
+
+	var account Account
+	if err := db.Where("name = ?", "alice").First(&account).Error; err != nil {
+		t.Fatalf("no error should happen when query, but got %v", err)
+	}
+	if account.ID != 1 || account.Name != "alice" || !account.Active {
+		t.Errorf("account should be scanned from canned rows, but got %+v", account)
+	}
+
+	if err := db.Model(&account).UpdateColumn("active", false).Error; err != nil {
+		t.Fatalf("no error should happen when update, but got %v", err)
+	}
+
+	statements := mock.Statements()
+	if len(statements) != 4 {
+		t.Fatalf("should record 4 statements, but got %v", statements)
+	}
+	if !statements[0].Query || statements[1].SQL != "BEGIN" || statements[2].Query || statements[3].SQL != "COMMIT" {
+		t.Errorf("should record query and exec statements in transaction, but got %v", statements)
+	}
+}
+
+func TestMockErrorAndStrict(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+	mock.Strict = true
+
+	failure := errors.New("connection reset")
+	deletion := mock.ExpectRegexp(`^DELETE FROM`).WillReturnError(failure)
+
+	if err := db.Delete(&Account{ID: 1}).Error; err == nil || !strings.Contains(err.Error(), failure.Error()) {
+		t.Errorf("should return the expected error, but got %v", err)
+	}
+	if called := deletion.Called(); called != 1 {
+		t.Errorf("delete should be called once, but got %d", called)
+	}
+
+	if err := db.Find(&[]Account{}).Error; err == nil {
+		t.Errorf("strict mock should fail unexpected statements")
+	}
+}
+
+func TestMockUnmet(t *testing.T) {
+	var (
+		_, mock, _ = aormtest.Open("sqlite3")
+		rec        recorder
+	)
+	mock.Expect("SELECT 1")
+	if mock.AssertExpectations(&rec) || len(rec.errors) != 1 {
+		t.Errorf("should report unmet expectation, but got %v", rec.errors)
+	}
+}
+
+type recorder struct {
+	errors []string
+}
+
+func (this *recorder) Helper() {}
+
+func (this *recorder) Errorf(format string, args ...interface{}) {
+	this.errors = append(this.errors, format)
+}
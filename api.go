@@ -1,6 +1,7 @@
 package aorm
 
 import (
+	"context"
 	"database/sql"
 	"reflect"
 )
@@ -18,6 +19,10 @@ type (
 		Begin() (*sql.Tx, error)
 	}
 
+	sqlDbTx interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	}
+
 	sqlTx interface {
 		Commit() error
 		Rollback() error
@@ -43,6 +43,20 @@ func (this *Association) Append(values ...interface{}) *Association {
 	return this.saveAssociations(values...)
 }
 
+// AppendWith append new associations for many2many storing payload values into the join table columns
+func (this *Association) AppendWith(value interface{}, payload interface{}) *Association {
+	if this.error != nil {
+		return this
+	}
+
+	if relationship := this.field.Relationship; relationship.Kind != "many_to_many" {
+		return this.addErr(fmt.Errorf("join table payload is only supported for many2many associations, but %q is %s", this.column, relationship.Kind))
+	} else if _, ok := relationship.JoinTableHandler.(JoinTablePayloader); !ok {
+		return this.addErr(fmt.Errorf("join table handler of %q does not support payload", this.column))
+	}
+	return this.saveAssociationsWith(payload, value)
+}
+
 // Replace replace current associations with new one
 func (this *Association) Replace(values ...interface{}) *Association {
 	if this.error != nil {
@@ -76,7 +90,7 @@ func (this *Association) Replace(values ...interface{}) *Association {
 	} else {
 		// Polymorphic Relations
 		if relationship.PolymorphicDBName != "" {
-			newDB = newDB.Where(fmt.Sprintf("%v = ?", scope.Quote(relationship.PolymorphicDBName)), relationship.PolymorphicValue)
+			newDB = newDB.Where(fmt.Sprintf("%v = ?", scope.Quote(relationship.PolymorphicDBName)), relationship.PolymorphicValue(scope.db.Context, scope.db.singularTable))
 		}
 
 		// Delete Relations except new created
@@ -311,6 +325,11 @@ func (this *Association) Count() int {
 
 // saveAssociations save passed values as associations
 func (this *Association) saveAssociations(values ...interface{}) *Association {
+	return this.saveAssociationsWith(nil, values...)
+}
+
+// saveAssociationsWith save passed values as associations with join table payload
+func (this *Association) saveAssociationsWith(payload interface{}, values ...interface{}) *Association {
 	var (
 		scope        = this.scope
 		field        = this.field
@@ -355,7 +374,12 @@ func (this *Association) saveAssociations(values ...interface{}) *Association {
 		}
 
 		if relationship.Kind == "many_to_many" {
-			err := relationship.JoinTableHandler.Add(relationship.JoinTableHandler, scope.NewDB(), scope.Value, reflectValue.Interface())
+			var err error
+			if payloader, ok := relationship.JoinTableHandler.(JoinTablePayloader); ok && payload != nil {
+				err = payloader.AddWith(relationship.JoinTableHandler, scope.NewDB(), scope.Value, reflectValue.Interface(), payload)
+			} else {
+				err = relationship.JoinTableHandler.Add(relationship.JoinTableHandler, scope.NewDB(), scope.Value, reflectValue.Interface())
+			}
 			if err != nil {
 				this.addErr(err)
 				return
@@ -0,0 +1,219 @@
+package aorm
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// batchReservedBindVars is the number of bind variables reserved to the values added by callbacks, e.g. the
+// timestamps and audit fields
+const batchReservedBindVars = 16
+
+// ErrInBatchesExclude is returned by UpdateInBatches and DeleteInBatches if the ids are excluded, because the
+// chunks selects the records by ids
+var ErrInBatchesExclude = errors.New("in batches: excluded ids are not supported")
+
+// FindInBatches finds the records in batches of size, ordered by primary key, and calls f for each batch. The
+// pages are selected by primary key greater than last record of previous batch, instead of offset, so the
+// records changed by f does not shift the next pages. If f returns an error, the iteration stops and returns it.
+//
+//	var users []User
+//	err := db.Where("active = ?", true).FindInBatches(&users, 500, func(tx *aorm.DB, batch int) error {
+//		for _, user := range users {
+//			...
+//		}
+//		return nil
+//	})
+func (s *DB) FindInBatches(dest interface{}, size int, f func(tx *DB, batch int) error) (err error) {
+	if size <= 0 {
+		return errors.New("find in batches: size must be greater than zero")
+	}
+	var (
+		scope  = s.NewScope(dest)
+		ms     = scope.Struct()
+		fields = ms.PrimaryFields
+	)
+	if len(fields) == 0 {
+		return errors.Errorf("find in batches: model %s does not have primary key", ms.Fqn())
+	}
+
+	var columns, orders []string
+	for _, field := range fields {
+		column := scope.QuotedTableName() + "." + scope.Quote(field.DBName)
+		columns = append(columns, column)
+		orders = append(orders, column+" ASC")
+	}
+	// the composite keys are compared as `a > ? OR (a = ? AND b > ?)`, because not all dialects supports row
+	// values comparison
+	var (
+		or     []string
+		equals []string
+	)
+	for _, column := range columns {
+		or = append(or, strings.Join(append(equals[:len(equals):len(equals)], column+" > ?"), " AND "))
+		equals = append(equals, column+" = ?")
+	}
+	cond := "((" + strings.Join(or, ") OR (") + "))"
+
+	query := s.Order(strings.Join(orders, ", "), true).Limit(size)
+	var last []interface{}
+	for batch := 1; ; batch++ {
+		q := query
+		if last != nil {
+			q = q.Where(cond, last...)
+		}
+		result := q.Find(dest)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		records := reflect.Indirect(reflect.ValueOf(dest))
+		count := records.Len()
+		if count == 0 {
+			return
+		}
+		if err = f(result, batch); err != nil {
+			return
+		}
+		if count < size {
+			return
+		}
+
+		record := records.Index(count - 1)
+		last = last[:0]
+		values := ms.GetID(record).Values()
+		for i := range values {
+			// the args of `a > ? OR (a = ? AND b > ?)`
+			for _, value := range values[:i+1] {
+				last = append(last, value.Raw())
+			}
+		}
+	}
+}
+
+// UpdateInBatches updates the attributes of records with ids, like Updates, splitting the ids into chunks that
+// respects the bind variables limit of dialect. The RowsAffected is the sum of all chunks.
+//
+//	db.Model(&User{}).UpdateInBatches(aorm.InID(ids...), map[string]interface{}{"active": false})
+func (s *DB) UpdateInBatches(ids IDSlicer, values interface{}, ignoreProtectedAttrs ...bool) *DB {
+	reserved := s.searchBindVars()
+	if m, ok := values.(map[string]interface{}); ok {
+		reserved += len(m)
+	} else if values != nil {
+		reserved += len(s.NewScope(values).Struct().Fields)
+	}
+	return s.inBatches(s.Val, ids, reserved, func(db *DB) *DB {
+		return db.Opt(OptForceSingleUpdate()).Updates(values, ignoreProtectedAttrs...)
+	})
+}
+
+// DeleteInBatches deletes the records of value with ids, like Delete, splitting the ids into chunks that
+// respects the bind variables limit of dialect. The RowsAffected is the sum of all chunks.
+//
+//	db.DeleteInBatches(&User{}, aorm.InID(ids...))
+func (s *DB) DeleteInBatches(value interface{}, ids IDSlicer) *DB {
+	return s.inBatches(value, ids, s.searchBindVars(), func(db *DB) *DB {
+		return db.Delete(value)
+	})
+}
+
+func (s *DB) inBatches(value interface{}, ids IDSlicer, reserved int, f func(db *DB) *DB) *DB {
+	var (
+		result = s.clone()
+		values = ids.Values()
+	)
+	result.RowsAffected = 0
+	if isExcludedID(ids) {
+		result.AddError(ErrInBatchesExclude)
+		return result
+	}
+	for _, id := range values {
+		if isExcludedID(id) {
+			result.AddError(ErrInBatchesExclude)
+			return result
+		}
+	}
+	if len(values) == 0 {
+		return result
+	}
+
+	ms := s.NewScope(value).Struct()
+	if len(ms.PrimaryFields) == 0 {
+		result.AddError(errors.Errorf("in batches: model %s does not have primary key", ms.Fqn()))
+		return result
+	}
+
+	size := (s.Dialect().MaxBindVars() - reserved - batchReservedBindVars) / len(ms.PrimaryFields)
+	if size <= 0 {
+		result.AddError(errors.New("in batches: too many bind variables"))
+		return result
+	}
+
+	for start := 0; start < len(values); start += size {
+		end := start + size
+		if end > len(values) {
+			end = len(values)
+		}
+		db := f(s.Where(&idsChunk{ms.PrimaryFields, values[start:end]}))
+		result.RowsAffected += db.RowsAffected
+		if db.Error != nil {
+			result.AddError(db.Error)
+			return result
+		}
+	}
+	return result
+}
+
+// isExcludedID returns if the ID or IDSlicer was excluded
+func isExcludedID(id interface{}) bool {
+	if e, ok := id.(interface{ excluded() bool }); ok {
+		return e.excluded()
+	}
+	return false
+}
+
+// searchBindVars returns the number of bind variables of search conditions
+func (s *DB) searchBindVars() (count int) {
+	if s.search == nil {
+		return
+	}
+	for _, clauses := range [][]*Clause{s.search.whereConditions, s.search.orConditions, s.search.notConditions} {
+		for _, clause := range clauses {
+			count += len(clause.Args)
+		}
+	}
+	return
+}
+
+// idsChunk is the where clause `IN` of ids
+type idsChunk struct {
+	fields []*StructField
+	ids    []ID
+}
+
+func (this *idsChunk) WhereClause(scope *Scope) (result Query) {
+	tableName := scope.QuotedTableName() + "."
+	if len(this.fields) == 1 {
+		marks := make([]string, len(this.ids))
+		for i, id := range this.ids {
+			marks[i] = "?"
+			result.AddArgs(id.Values()[0].Raw())
+		}
+		result.Query = tableName + scope.Quote(this.fields[0].DBName) + " IN (" + strings.Join(marks, ", ") + ")"
+		return
+	}
+
+	var q []string
+	for _, id := range this.ids {
+		var and []string
+		for i, value := range id.Values() {
+			and = append(and, tableName+scope.Quote(this.fields[i].DBName)+" = ?")
+			result.AddArgs(value.Raw())
+		}
+		q = append(q, "("+strings.Join(and, " AND ")+")")
+	}
+	result.Query = "(" + strings.Join(q, " OR ") + ")"
+	return
+}
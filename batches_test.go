@@ -0,0 +1,116 @@
+package aorm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+	"github.com/moisespsena-go/aorm/aormtest"
+)
+
+type BatchUser struct {
+	ID   int
+	Name string
+}
+
+type BatchMembership struct {
+	GroupID int `sql:"primary_key;auto_increment:false"`
+	UserID  int `sql:"primary_key;auto_increment:false"`
+}
+
+func TestFindInBatches(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+	mock.ExpectRegexp(`^SELECT .* FROM "aorm_test_batch_users" .*ORDER BY "aorm_test_batch_users"\."id" ASC LIMIT 2`).
+		WillReturnRows(aormtest.NewRows("id", "name").AddRow(1, "a").AddRow(2, "b"))
+	mock.ExpectRegexp(`^SELECT .*"aorm_test_batch_users"\."id" > \?.* ORDER BY "aorm_test_batch_users"\."id" ASC LIMIT 2`).
+		WithArgs(aormtest.AnyArg).
+		WillReturnRows(aormtest.NewRows("id", "name").AddRow(3, "c"))
+
+	var (
+		users   []BatchUser
+		ids     []int
+		batches int
+	)
+	err := db.FindInBatches(&users, 2, func(tx *aorm.DB, batch int) error {
+		batches = batch
+		for _, user := range users {
+			ids = append(ids, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("no error should happen when find in batches, but got %v", err)
+	}
+	if batches != 2 || len(ids) != 3 || ids[2] != 3 {
+		t.Errorf("should find 3 users in 2 batches, but got %v in %d batches", ids, batches)
+	}
+}
+
+func TestFindInBatchesCompositeKey(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+	mock.ExpectRegexp(`^SELECT .* FROM "aorm_test_batch_memberships" .*LIMIT 2`).
+		WillReturnRows(aormtest.NewRows("group_id", "user_id").AddRow(1, 1).AddRow(1, 2))
+	mock.ExpectRegexp(`^SELECT .*\("aorm_test_batch_memberships"\."group_id" > \?\) OR \("aorm_test_batch_memberships"\."group_id" = \? AND "aorm_test_batch_memberships"\."user_id" > \?\).* LIMIT 2`).
+		WithArgs(1, 1, 2).
+		WillReturnRows(aormtest.NewRows("group_id", "user_id"))
+
+	var memberships []BatchMembership
+	if err := db.FindInBatches(&memberships, 2, func(tx *aorm.DB, batch int) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("no error should happen when find in batches by composite key, but got %v", err)
+	}
+}
+
+func TestDeleteInBatches(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+	mock.ExpectRegexp(`^DELETE FROM "aorm_test_batch_users" +WHERE .*"aorm_test_batch_users"\."id" IN \(`).AnyTimes().WillReturnResult(0, 10)
+
+	var ids []aorm.ID
+	for i := 1; i <= 1500; i++ {
+		ids = append(ids, aorm.NewValuedId(aorm.IntId(i)))
+	}
+
+	result := db.DeleteInBatches(&BatchUser{}, aorm.InID(ids...))
+	if result.Error != nil {
+		t.Fatalf("no error should happen when delete in batches, but got %v", result.Error)
+	}
+	if result.RowsAffected != 20 {
+		t.Errorf("rows affected should be the sum of chunks, but got %d", result.RowsAffected)
+	}
+
+	var chunks []int
+	for _, stmt := range mock.Statements() {
+		if strings.HasPrefix(stmt.SQL, "DELETE") {
+			chunks = append(chunks, len(stmt.Args))
+		}
+	}
+	if len(chunks) != 2 || chunks[0]+chunks[1] != 1500 || chunks[0] > 999 {
+		t.Errorf("ids should be split into chunks respecting the sqlite limit, but got %v", chunks)
+	}
+}
+
+func TestUpdateInBatches(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+	mock.ExpectRegexp(`^UPDATE "aorm_test_batch_users" SET "name" = \? +WHERE .*"aorm_test_batch_users"\."id" IN \(\?, \?\)`).
+		WithArgs("x", 1, 2).
+		WillReturnResult(0, 2)
+
+	ids := []aorm.ID{aorm.NewValuedId(aorm.IntId(1)), aorm.NewValuedId(aorm.IntId(2))}
+	result := db.Model(&BatchUser{}).UpdateInBatches(aorm.InID(ids...), map[string]interface{}{"name": "x"})
+	if result.Error != nil || result.RowsAffected != 2 {
+		t.Errorf("should update in batches, but got %v, %v", result.RowsAffected, result.Error)
+	}
+}
+
+func TestDeleteInBatchesExcluded(t *testing.T) {
+	db, _ := aormtest.New(t, "sqlite3")
+	ids := []aorm.ID{aorm.NewValuedId(aorm.IntId(1))}
+
+	if err := db.DeleteInBatches(&BatchUser{}, aorm.InID(ids...).Exclude()).Error; err != aorm.ErrInBatchesExclude {
+		t.Errorf("excluded ids should not be deleted in batches, but got %v", err)
+	}
+	if err := db.Model(&BatchUser{}).UpdateInBatches(aorm.InID(ids[0].Exclude()), map[string]interface{}{"name": "x"}).Error; err != aorm.ErrInBatchesExclude {
+		t.Errorf("excluded id should not be updated in batches, but got %v", err)
+	}
+}
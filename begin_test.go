@@ -0,0 +1,25 @@
+package aorm_test
+
+import (
+	"testing"
+)
+
+func TestBeginKeepsDialectOfParent(t *testing.T) {
+	DB.Exec("DROP TABLE IF EXISTS begin_items")
+	DB.Exec("CREATE TABLE begin_items (id integer)")
+
+	tx := DB.Begin()
+	if tx.Dialect() == DB.Dialect() {
+		t.Errorf("transaction should not share the dialect of parent db")
+	}
+	if !tx.Dialect().HasTable("begin_items") {
+		t.Errorf("dialect of transaction should find table")
+	}
+	if err := tx.Commit().Error; err != nil {
+		t.Fatalf("no error should happen when commit, but got %v", err)
+	}
+
+	if !DB.Dialect().HasTable("begin_items") {
+		t.Errorf("dialect of parent db should use the parent connection after transaction is committed")
+	}
+}
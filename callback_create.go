@@ -19,8 +19,10 @@ func init() {
 	DefaultCallback.Create().Register("aorm:save_before_associations", saveBeforeAssociationsCallback)
 	DefaultCallback.Create().Register("aorm:update_time_stamp", updateTimeStampForCreateCallback)
 	DefaultCallback.Create().Register("aorm:audited", auditedForCreateCallback)
+	DefaultCallback.Create().Register("aorm:tenant", tenantForCreateCallback)
 	DefaultCallback.Create().Register("aorm:create", createCallback)
 	DefaultCallback.Create().Register("aorm:force_reload_after_create", forceReloadAfterCreateCallback)
+	DefaultCallback.Create().Register("aorm:reload_generated_columns", reloadGeneratedColumnsCallback)
 	DefaultCallback.Create().Register("aorm:create_children", createChildrenCallback)
 	DefaultCallback.Create().Register("aorm:save_after_associations", saveAfterAssociationsCallback)
 	DefaultCallback.Create().Register("aorm:after_create", afterCreateCallback)
@@ -93,6 +95,10 @@ func createCallback(scope *Scope) {
 						}
 					}
 				} else if field.IsPrimaryKey {
+					if field.IsBlank && !hasInternalId && field.TagSettings.Flag("AUTO_INCREMENT") {
+						// the value is assigned by database and set from last insert id
+						continue
+					}
 					if hasInternalId {
 						columns = append(columns, scope.Quote(field.DBName))
 						placeholders = append(placeholders, scope.AddToVars(RawOfId(id.(ID), field.Name)))
@@ -151,7 +157,7 @@ func createCallback(scope *Scope) {
 	if len(columns) == 0 {
 		scope.Raw(fmt.Sprintf(
 			"INSERT INTO %v %v%v%v",
-			quotedTableName,
+			scope.QuotedQualifiedTableName(),
 			scope.Dialect().DefaultValueStr(),
 			addExtraSpaceIfExist(extraOption),
 			addExtraSpaceIfExist(lastInsertIDReturningSuffix),
@@ -159,7 +165,7 @@ func createCallback(scope *Scope) {
 	} else {
 		scope.Raw(fmt.Sprintf(
 			"INSERT INTO %v (%v) VALUES (%v)%v%v",
-			scope.QuotedTableName(),
+			scope.QuotedQualifiedTableName(),
 			strings.Join(columns, ","),
 			strings.Join(placeholders, ","),
 			addExtraSpaceIfExist(extraOption),
@@ -226,6 +232,51 @@ func forceReloadAfterCreateCallback(scope *Scope) {
 	}
 }
 
+// reloadGeneratedColumnsCallback will reload the generated columns of created or updated record, and set it
+// back to current object
+func reloadGeneratedColumnsCallback(scope *Scope) {
+	if scope.HasError() || scope.checkDryRun() || len(scope.Struct().GeneratedFields) == 0 {
+		return
+	}
+	if scope.Operation == OpUpdate && scope.db.RowsAffected == 0 {
+		return
+	}
+	if scope.IndirectValue().Kind() != reflect.Struct || scope.PrimaryKeyZero() {
+		return
+	}
+
+	var (
+		instance       = scope.Instance()
+		query          = scope.NewDB().NewScope(scope.Value)
+		columns, conds []string
+		dests          []interface{}
+	)
+	for _, field := range scope.PrimaryFields() {
+		if !field.Field.IsValid() {
+			return
+		}
+		conds = append(conds, scope.Quote(field.DBName)+" = "+query.AddToVars(field.Field.Interface()))
+	}
+	for _, f := range scope.Struct().GeneratedFields {
+		if field := instance.FieldsMap[f.Name]; field != nil && field.Field.CanAddr() {
+			columns = append(columns, scope.Quote(f.DBName))
+			dests = append(dests, field.Field.Addr().Interface())
+		}
+	}
+	if len(columns) == 0 {
+		return
+	}
+
+	query.Raw(fmt.Sprintf(
+		"SELECT %v FROM %v WHERE %v",
+		strings.Join(columns, ","),
+		scope.QuotedQualifiedTableName(),
+		strings.Join(conds, " AND "),
+	))
+	query.log(LOG_READ)
+	scope.Err(scope.SQLDB().QueryRow(query.Query.Query, query.Query.Args...).Scan(dests...))
+}
+
 // createChildrenCallback will creates children
 func createChildrenCallback(scope *Scope) {
 	if scope.HasError() {
@@ -279,6 +330,10 @@ func afterCreateCallback(scope *Scope) {
 	}
 
 	scope.CallMethod("AfterSave")
+
+	if !scope.HasError() {
+		scope.trackTxRecord(OpCreate)
+	}
 }
 
 func SetIdCallback(scope *Scope) {
@@ -62,7 +62,7 @@ func deleteCallback(scope *Scope) {
 
 		scope.Raw(fmt.Sprintf(
 			"UPDATE %v SET %v%v%v",
-			scope.QuotedTableName(),
+			scope.QuotedQualifiedTableName(),
 			strings.Join(pairs, ", "),
 			addExtraSpaceIfExist(scope.CombinedConditionSql()),
 			addExtraSpaceIfExist(extraOption),
@@ -71,7 +71,7 @@ func deleteCallback(scope *Scope) {
 		cond := scope.CombinedConditionSql()
 		scope.Raw(fmt.Sprintf(
 			"DELETE FROM %v%v%v",
-			scope.QuotedTableName(),
+			scope.QuotedQualifiedTableName(),
 			addExtraSpaceIfExist(cond),
 			addExtraSpaceIfExist(extraOption),
 		))
@@ -89,4 +89,7 @@ func afterDeleteCallback(scope *Scope) {
 	if !scope.HasError() {
 		scope.CallMethod("AfterDelete")
 	}
+	if !scope.HasError() {
+		scope.trackTxRecord(OpDelete)
+	}
 }
@@ -55,7 +55,7 @@ func inlinePreload(rootScope, scope *Scope, index [][]int) {
 		)
 
 		for idx, preloadField := range preloadFields {
-			var currentOptions *InlinePreloadOptions
+			var currentOptions = &InlinePreloadOptions{}
 
 			// if not preloaded
 			if preloadKey := strings.Join(preloadFields[:idx+1], "."); !preloadedMap[preloadKey] {
@@ -70,6 +70,12 @@ func inlinePreload(rootScope, scope *Scope, index [][]int) {
 					currentScope.handleBelongsToInlinePreload(rootScope, scope, []string{}, field, currentIndex, currentOptions)
 					currentModelStruct = currentScope.Struct()
 					preloadedMap[preloadKey] = true
+					rootScope.inlinePreloads.addPath(preloadKey)
+				} else if ok && field.Relationship != nil && field.Relationship.Kind == "many_to_many" {
+					// many2many relations can't be joined into a single row, so the path is loaded by preload
+					// callback after query, that skips the parent fields joined into the query
+					scope.Search.Preload(preload.schema, preload.options)
+					break
 				} else if currentModelStruct.virtualFields[preloadField] != nil {
 					vf := currentModelStruct.virtualFields[preloadField]
 					currentIndex = append(currentIndex, []int{(vf.StructIndex + 1) * -1})
@@ -79,7 +85,7 @@ func inlinePreload(rootScope, scope *Scope, index [][]int) {
 					currentModelStruct = vf.Model
 					preloadedMap[preloadKey] = true
 				} else {
-					scope.Err(fmt.Errorf("can't inline preload field %s for %s", preloadField, currentModelStruct.Type))
+					rootScope.Err(fmt.Errorf("can't inline preload field %s for %s", preloadField, currentModelStruct.Type))
 					return
 				}
 			}
@@ -94,7 +100,7 @@ func (scope *Scope) handleBelongsToInlinePreload(rootScope, parentScope *Scope,
 		relation = field.Relationship
 		query    = make([]string, len(relation.ForeignFieldNames))
 		rqtn     = parentScope.QuotedTableName()
-		qtn      = scope.QuotedTableName()
+		qtn      = scope.QuotedQualifiedTableName()
 		dbName   = rootScope.inlinePreloads.Next(path...)
 	)
 	scope.Search.Table(dbName)
@@ -107,9 +113,15 @@ func (scope *Scope) handleBelongsToInlinePreload(rootScope, parentScope *Scope,
 		query[i] = fmt.Sprintf("%v.%v = %v.%v", dbName, relation.AssociationForeignDBNames[i], rqtn, fk)
 	}
 
-	joinQuery := fmt.Sprintf("%s JOIN %v AS %v ON ", options.Join, qtn, dbName) + strings.Join(query, ", ")
+	var joinArgs []interface{}
+	if column, tenant, ok := rootScope.tenantOf(scope.Struct()); ok {
+		query = append(query, fmt.Sprintf("%v.%v = ?", dbName, column))
+		joinArgs = append(joinArgs, tenant)
+	}
+
+	joinQuery := fmt.Sprintf("%s JOIN %v AS %v ON ", options.Join, qtn, dbName) + strings.Join(query, " AND ")
 
-	rootScope.Search.Joins(joinQuery)
+	rootScope.Search.Joins(joinQuery, joinArgs...)
 	inlineRelated := &InlinePreloader{
 		ID:          dbName,
 		Scope:       scope,
@@ -137,7 +149,7 @@ func (scope *Scope) virtualFieldInlinePreload(rootScope, parentScope *Scope, pat
 	path = append(path, field.FieldName)
 	var (
 		rqtn    = parentScope.QuotedTableName()
-		qtn     = scope.QuotedTableName()
+		qtn     = scope.QuotedQualifiedTableName()
 		dbName  = rootScope.inlinePreloads.Next(path...)
 		builder = &InlinePreloadBuilder{&options.Conditions, &InlinePreloadInfo{RootScope: rootScope, ParentScope: parentScope, Scope: scope}}
 	)
@@ -54,8 +54,14 @@ func preloadCallback(scope *Scope) {
 				continue
 			}
 
+			preloadKey := strings.Join(preloadFields[:idx+1], ".")
+			if scope.inlinePreloads.Joined(preloadKey) {
+				// loaded by inline preload
+				preloadedMap[preloadKey] = true
+			}
+
 			// if not preloaded
-			if preloadKey := strings.Join(preloadFields[:idx+1], "."); !preloadedMap[preloadKey] {
+			if !preloadedMap[preloadKey] {
 
 				// assign search conditions to last preload
 				if idx == len(preloadFields)-1 {
@@ -306,8 +312,35 @@ func (scope *Scope) handleManyToManyPreload(field *Field, conditions *Conditions
 	newScope := scope.New(reflect.New(fieldType).Interface())
 	preloadDB = preloadDB.Table(newScope.TableName()).Model(newScope.Value)
 
+	var (
+		payloadStruct *ModelStruct
+		payloadFields []*StructField
+	)
+	if payloader, ok := joinTableHandler.(JoinTablePayloader); ok {
+		if payloadStruct = payloader.Payload(); payloadStruct != nil {
+			payloadFields = payloader.PayloadFields()
+		}
+	}
+
 	if len(preloadDB.search.selects) == 0 {
-		preloadDB = preloadDB.Select(IQ("{}.*"))
+		var (
+			selectQuery     = "{}.*"
+			quotedJoinTable = scope.Quote(joinTableHandler.Table(scope.db))
+		)
+		// select join table source foreign keys, used to link the results to sources
+		for _, key := range sourceKeys {
+			selectQuery += fmt.Sprintf(", %v.%v AS %v", quotedJoinTable, scope.Quote(key.DBName), scope.Quote(joinTableForeignKeyPrefix+key.DBName))
+		}
+		// select join table payload columns
+		if len(payloadFields) > 0 {
+			for _, f := range payloadFields {
+				selectQuery += fmt.Sprintf(", %v.%v AS %v", quotedJoinTable, scope.Quote(f.DBName), scope.Quote(joinTablePayloadPrefix+f.DBName))
+			}
+		}
+		preloadDB = preloadDB.Select(IQ(selectQuery))
+	} else {
+		// custom selects does not contains payload columns
+		payloadFields = nil
 	}
 
 	preloadDB = joinTableHandler.JoinWith(joinTableHandler, preloadDB, scope.Value)
@@ -330,18 +363,42 @@ func (scope *Scope) handleManyToManyPreload(field *Field, conditions *Conditions
 		for _, key := range sourceKeys {
 			joinTableFields = append(joinTableFields, &Field{
 				StructField: &StructField{
-					DBName:   key.DBName,
+					DBName:   joinTableForeignKeyPrefix + key.DBName,
 					IsNormal: true,
 					Struct: reflect.StructField{
 						Type: key.AssociationField.Struct.Type,
 					},
 					Assigner: key.AssociationField.Assigner,
 				},
-				Field: instance.FieldsMap[key.AssociationField.Name].Field,
+				Field: reflect.New(key.AssociationField.Struct.Type).Elem(),
 			})
 		}
 
-		scope.scan(rows, columns, append(instance.Fields, joinTableFields...), nil)
+		// register join table payload fields
+		var (
+			payloadFieldsValues []*Field
+			payload             reflect.Value
+		)
+		if len(payloadFields) > 0 {
+			payload = reflect.New(payloadStruct.Type)
+			payloadInstance := InstanceOf(payload.Interface())
+			for _, f := range payloadFields {
+				structField := f.clone()
+				structField.DBName = joinTablePayloadPrefix + f.DBName
+				payloadFieldsValues = append(payloadFieldsValues, &Field{
+					StructField: structField,
+					Field:       payloadInstance.FieldsMap[f.Name].Field,
+				})
+			}
+		}
+
+		scope.scan(rows, columns, append(append(instance.Fields, joinTableFields...), payloadFieldsValues...), nil)
+
+		if payload.IsValid() {
+			if setter, ok := elem.Addr().Interface().(JoinTablePayloadSetter); ok {
+				setter.SetAormJoinTablePayload(payload.Interface())
+			}
+		}
 
 		scope.New(elem.Addr().Interface()).
 			InstanceSet("aorm:skip_query_callback", true).
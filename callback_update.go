@@ -16,7 +16,9 @@ func init() {
 	DefaultCallback.Update().Register("aorm:save_before_associations", saveBeforeAssociationsCallback)
 	DefaultCallback.Update().Register("aorm:update_time_stamp", updateTimeStampForUpdateCallback)
 	DefaultCallback.Update().Register("aorm:audited", auditedForUpdateCallback)
+	DefaultCallback.Update().Register("aorm:tenant", tenantForUpdateCallback)
 	DefaultCallback.Update().Register("aorm:update", updateCallback)
+	DefaultCallback.Update().Register("aorm:reload_generated_columns", reloadGeneratedColumnsCallback)
 	DefaultCallback.Update().Register("aorm:update_children", updateChildrenCallback)
 	DefaultCallback.Update().Register("aorm:save_after_associations", saveAfterAssociationsCallback)
 	DefaultCallback.Update().Register("aorm:after_update", afterUpdateCallback)
@@ -126,7 +128,7 @@ func updateCallback(scope *Scope) {
 
 	query := fmt.Sprintf(
 		"UPDATE %v SET %v%v%v",
-		scope.QuotedTableName(),
+		scope.QuotedQualifiedTableName(),
 		strings.Join(sqls, ", "),
 		addExtraSpaceIfExist(scope.CombinedConditionSql()),
 		addExtraSpaceIfExist(extraOption),
@@ -191,5 +193,8 @@ func afterUpdateCallback(scope *Scope) {
 		if !scope.HasError() {
 			scope.CallMethod("AfterSave")
 		}
+		if !scope.HasError() {
+			scope.trackTxRecord(OpUpdate)
+		}
 	}
 }
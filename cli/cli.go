@@ -0,0 +1,410 @@
+// Package cli implements the `aorm` command line tool.
+//
+//	aorm [-config aorm.yml] migrate up [-n N]
+//	aorm [-config aorm.yml] migrate down [-n N]
+//	aorm [-config aorm.yml] migrate status
+//	aorm [-config aorm.yml] migrate create NAME
+//	aorm [-config aorm.yml] schema dump [-out FILE]
+//	aorm [-config aorm.yml] schema diff
+//	aorm [-config aorm.yml] db inspect [-go] [-package NAME] [TABLE...]
+//
+// The migrations and models are registered by the go package of config into the migrate.Default registry. The
+// commands that requires them are executed by a generated main, written into the `.aorm` directory beside the
+// config file, that imports this package.
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+	_ "github.com/moisespsena-go/aorm/dialects/mssql"
+	_ "github.com/moisespsena-go/aorm/dialects/mysql"
+	_ "github.com/moisespsena-go/aorm/dialects/postgres"
+	_ "github.com/moisespsena-go/aorm/dialects/sqlite"
+	"github.com/moisespsena-go/aorm/migrate"
+	"github.com/moisespsena-go/aorm/reverse"
+)
+
+const usage = `usage: aorm [-config FILE] COMMAND
+
+commands:
+  migrate up [-n N]      apply the pending migrations
+  migrate down [-n N]    revert the last N applied migrations (default 1)
+  migrate status         show the state of migrations
+  migrate create NAME    create a new migration file
+  schema dump [-out F]   write the DDL script of registered models
+  schema diff            compare the registered models against database
+  db inspect [-go] [T..] show the tables of database
+`
+
+// CLI is the command line tool
+type CLI struct {
+	Stdout, Stderr io.Writer
+	// Loaded is true if the config package is linked into the binary
+	Loaded bool
+
+	configPath string
+}
+
+// Main runs the command line with the process arguments and exits
+func Main(loaded bool) {
+	os.Exit((&CLI{Stdout: os.Stdout, Stderr: os.Stderr, Loaded: loaded}).Run(os.Args[1:]))
+}
+
+// Run runs the command line and returns the exit code
+func (this *CLI) Run(args []string) int {
+	flags := flag.NewFlagSet("aorm", flag.ContinueOnError)
+	flags.SetOutput(this.Stderr)
+	flags.Usage = func() {
+		fmt.Fprint(this.Stderr, usage)
+	}
+	configPath := os.Getenv("AORM_CONFIG")
+	if configPath == "" {
+		configPath = DefaultConfigFile
+	}
+	flags.StringVar(&configPath, "config", configPath, "config file")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if args = flags.Args(); len(args) < 2 {
+		flags.Usage()
+		return 2
+	}
+
+	var err error
+	if this.configPath, err = filepath.Abs(configPath); err != nil {
+		return this.fail(err)
+	}
+	cfg, err := LoadConfig(this.configPath)
+	if err != nil {
+		return this.fail(err)
+	}
+
+	command := args[0] + " " + args[1]
+	switch command {
+	case "migrate up", "migrate down", "migrate status", "schema dump", "schema diff":
+		if !this.Loaded && cfg.Package != "" {
+			code, err := this.delegate(cfg, args)
+			if err != nil {
+				return this.fail(err)
+			}
+			return code
+		}
+	}
+
+	switch command {
+	case "migrate up":
+		err = this.migrateUp(cfg, args[2:])
+	case "migrate down":
+		err = this.migrateDown(cfg, args[2:])
+	case "migrate status":
+		err = this.migrateStatus(cfg)
+	case "migrate create":
+		err = this.migrateCreate(cfg, args[2:])
+	case "schema dump":
+		err = this.schemaDump(cfg, args[2:])
+	case "schema diff":
+		err = this.schemaDiff(cfg)
+	case "db inspect":
+		err = this.dbInspect(cfg, args[2:])
+	default:
+		fmt.Fprintf(this.Stderr, "aorm: unknown command %q\n", command)
+		flags.Usage()
+		return 2
+	}
+	if err != nil {
+		return this.fail(err)
+	}
+	return 0
+}
+
+func (this *CLI) fail(err error) int {
+	fmt.Fprintln(this.Stderr, "aorm:", err)
+	return 1
+}
+
+func (this *CLI) open(cfg *Config) (db *aorm.DB, err error) {
+	if db, err = aorm.Open(cfg.Dialect, cfg.DSN); err != nil {
+		return nil, errors.Wrap(err, "open database")
+	}
+	if cfg.Schema != "" && cfg.Dialect == "postgres" {
+		db = db.WithSchema(cfg.Schema)
+	}
+	return
+}
+
+func (this *CLI) countFlags(name string, args []string, defaultValue int) (n int, err error) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(this.Stderr)
+	flags.IntVar(&n, "n", defaultValue, "number of migrations (0 for all)")
+	err = flags.Parse(args)
+	return
+}
+
+func (this *CLI) migrateUp(cfg *Config, args []string) (err error) {
+	n, err := this.countFlags("migrate up", args, 0)
+	if err != nil {
+		return
+	}
+	db, err := this.open(cfg)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	applied, err := migrate.New(db).Up(n)
+	for _, m := range applied {
+		fmt.Fprintln(this.Stdout, "applied", m.ID)
+	}
+	if err == nil && len(applied) == 0 {
+		fmt.Fprintln(this.Stdout, "no pending migrations")
+	}
+	return
+}
+
+func (this *CLI) migrateDown(cfg *Config, args []string) (err error) {
+	n, err := this.countFlags("migrate down", args, 1)
+	if err != nil {
+		return
+	}
+	db, err := this.open(cfg)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	reverted, err := migrate.New(db).Down(n)
+	for _, m := range reverted {
+		fmt.Fprintln(this.Stdout, "reverted", m.ID)
+	}
+	if err == nil && len(reverted) == 0 {
+		fmt.Fprintln(this.Stdout, "no applied migrations")
+	}
+	return
+}
+
+func (this *CLI) migrateStatus(cfg *Config) (err error) {
+	db, err := this.open(cfg)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	status, err := migrate.New(db).Status()
+	if err != nil {
+		return
+	}
+	w := tabwriter.NewWriter(this.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS")
+	for _, s := range status {
+		state := "pending"
+		if s.AppliedAt != nil {
+			state = "applied at " + s.AppliedAt.Format(time.RFC3339)
+			if s.Migration == nil {
+				state += " (not registered)"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\n", s.ID, state)
+	}
+	return w.Flush()
+}
+
+var (
+	migrationNameRegexp = regexp.MustCompile(`[^a-z0-9]+`)
+
+	migrationTemplate = `package %s
+
+import (
+	"github.com/moisespsena-go/aorm"
+	"github.com/moisespsena-go/aorm/migrate"
+)
+
+func init() {
+	migrate.Register(&migrate.Migration{
+		ID: %q,
+		Up: func(db *aorm.DB) error {
+			return nil
+		},
+		Down: func(db *aorm.DB) error {
+			return nil
+		},
+	})
+}
+`
+)
+
+func (this *CLI) migrateCreate(cfg *Config, args []string) (err error) {
+	if len(args) != 1 {
+		return errors.New("migrate create: NAME is required")
+	}
+	name := strings.Trim(migrationNameRegexp.ReplaceAllString(strings.ToLower(args[0]), "_"), "_")
+	if name == "" {
+		return errors.Errorf("migrate create: invalid NAME %q", args[0])
+	}
+	id := aorm.NowFunc().UTC().Format("20060102150405") + "_" + name
+
+	if err = os.MkdirAll(cfg.Migrations, 0755); err != nil {
+		return
+	}
+	pkg := migrationNameRegexp.ReplaceAllString(strings.ToLower(filepath.Base(cfg.Migrations)), "")
+	if pkg == "" || (pkg[0] >= '0' && pkg[0] <= '9') {
+		pkg = "migrations"
+	}
+	path := filepath.Join(cfg.Migrations, id+".go")
+	if err = os.WriteFile(path, []byte(fmt.Sprintf(migrationTemplate, pkg, id)), 0644); err != nil {
+		return
+	}
+	fmt.Fprintln(this.Stdout, "created", path)
+	return
+}
+
+func (this *CLI) models() ([]interface{}, error) {
+	models := migrate.Default.Models()
+	if len(models) == 0 {
+		return nil, errors.New("no models registered, see migrate.RegisterModels")
+	}
+	return models, nil
+}
+
+func (this *CLI) schemaDump(cfg *Config, args []string) (err error) {
+	var out string
+	flags := flag.NewFlagSet("schema dump", flag.ContinueOnError)
+	flags.SetOutput(this.Stderr)
+	flags.StringVar(&out, "out", "", "output file (defaults to stdout)")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+	models, err := this.models()
+	if err != nil {
+		return
+	}
+
+	db := aorm.FakeDB(cfg.Dialect)
+	if cfg.Schema != "" && cfg.Dialect == "postgres" {
+		db = db.WithSchema(cfg.Schema)
+	}
+	script, err := db.DumpSchema(models...)
+	if err != nil {
+		return
+	}
+	if out == "" {
+		_, err = io.WriteString(this.Stdout, script)
+		return
+	}
+	return os.WriteFile(out, []byte(script), 0644)
+}
+
+func (this *CLI) schemaDiff(cfg *Config) (err error) {
+	models, err := this.models()
+	if err != nil {
+		return
+	}
+	db, err := this.open(cfg)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	changes, err := reverse.Diff(db, cfg.Schema, models...)
+	if err != nil {
+		return
+	}
+	if len(changes) == 0 {
+		fmt.Fprintln(this.Stdout, "no changes")
+	}
+	for _, change := range changes {
+		fmt.Fprintln(this.Stdout, change)
+	}
+	return
+}
+
+func (this *CLI) dbInspect(cfg *Config, args []string) (err error) {
+	var (
+		goSource bool
+		pkg      string
+	)
+	flags := flag.NewFlagSet("db inspect", flag.ContinueOnError)
+	flags.SetOutput(this.Stderr)
+	flags.BoolVar(&goSource, "go", false, "write the go models of tables")
+	flags.StringVar(&pkg, "package", "models", "package name of go models")
+	if err = flags.Parse(args); err != nil {
+		return
+	}
+
+	db, err := this.open(cfg)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	tables, err := reverse.Inspect(db, cfg.Schema, flags.Args()...)
+	if err != nil {
+		return
+	}
+	if goSource {
+		return reverse.Generate(this.Stdout, pkg, tables)
+	}
+	for _, table := range tables {
+		printTable(this.Stdout, table)
+	}
+	return
+}
+
+func printTable(w io.Writer, table *reverse.Table) {
+	fmt.Fprintln(w, "table", table.Name)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, column := range table.Columns {
+		typ := column.Type
+		if column.Size > 0 {
+			typ += fmt.Sprintf("(%d)", column.Size)
+		}
+		var attrs []string
+		if table.IsPrimaryKey(column.Name) {
+			attrs = append(attrs, "primary key")
+		}
+		if column.AutoIncrement {
+			attrs = append(attrs, "auto increment")
+		}
+		if !column.Nullable {
+			attrs = append(attrs, "not null")
+		}
+		if column.Default != nil {
+			attrs = append(attrs, "default "+*column.Default)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", column.Name, typ, strings.Join(attrs, " "))
+	}
+	tw.Flush()
+	for _, ix := range table.Indexes {
+		kind := "index"
+		if ix.Unique {
+			kind = "unique index"
+		}
+		fmt.Fprintf(w, "  %s %s (%s)", kind, ix.Name, strings.Join(ix.Columns, ", "))
+		if ix.Where != "" {
+			fmt.Fprintf(w, " where %s", ix.Where)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, fk := range table.ForeignKeys {
+		fmt.Fprintf(w, "  foreign key %s (%s) references %s (%s)", fk.Name, strings.Join(fk.Columns, ", "),
+			fk.RefTable, strings.Join(fk.RefColumns, ", "))
+		if fk.OnDelete != "" {
+			fmt.Fprintf(w, " on delete %s", fk.OnDelete)
+		}
+		if fk.OnUpdate != "" {
+			fmt.Fprintf(w, " on update %s", fk.OnUpdate)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w)
+}
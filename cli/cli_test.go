@@ -0,0 +1,59 @@
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/aorm/cli"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "aorm.yml")
+	os.Setenv("AORM_TEST_DSN", "file:test.db")
+	defer os.Unsetenv("AORM_TEST_DSN")
+	if err := os.WriteFile(path, []byte("dialect: sqlite3\ndsn: ${AORM_TEST_DSN}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := cli.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("no error should happen when load config, but got %v", err)
+	}
+	if cfg.DSN != "file:test.db" {
+		t.Errorf("environment variables should be expanded, but got %q", cfg.DSN)
+	}
+	if cfg.Migrations != filepath.Join(dir, "migrations") {
+		t.Errorf("migrations dir should be relative to config, but got %q", cfg.Migrations)
+	}
+}
+
+func TestMigrateCreate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "aorm.yml")
+	if err := os.WriteFile(path, []byte("dialect: sqlite3\nmigrations: db/migrations\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	c := &cli.CLI{Stdout: &stdout, Stderr: &stderr}
+	if code := c.Run([]string{"-config", path, "migrate", "create", "Add Users"}); code != 0 {
+		t.Fatalf("migrate create should succeed, but got %d: %s", code, stderr.String())
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "db", "migrations", "*_add_users.go"))
+	if len(files) != 1 {
+		t.Fatalf("migration file should be created, but got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if src := string(data); !strings.HasPrefix(src, "package migrations\n") || !strings.Contains(src, "migrate.Register(") {
+		t.Errorf("migration file should register the migration, but got:\n%s", src)
+	}
+
+	if code := c.Run([]string{"-config", path, "unknown", "command"}); code != 2 {
+		t.Errorf("unknown command should exit with 2, but got %d", code)
+	}
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultConfigFile is the config file name used if not set by `-config` flag or AORM_CONFIG environment variable
+const DefaultConfigFile = "aorm.yml"
+
+// Config is the command line configuration. The environment variables of values are expanded.
+//
+//	dialect: postgres
+//	dsn: ${DATABASE_URL}
+//	schema: public
+//	package: example.com/app/migrations
+//	migrations: migrations
+type Config struct {
+	Dialect string `yaml:"dialect"`
+	DSN     string `yaml:"dsn"`
+	// Schema is the database schema used by postgres
+	Schema string `yaml:"schema"`
+	// Package is the import path of go package that registers the migrations and models
+	Package string `yaml:"package"`
+	// Migrations is the directory of migration files created by `migrate create`, relative to config file
+	Migrations string `yaml:"migrations"`
+
+	// Dir is the directory of config file
+	Dir string `yaml:"-"`
+}
+
+// LoadConfig reads the config file
+func LoadConfig(path string) (cfg *Config, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read config")
+	}
+	cfg = &Config{}
+	if err = yaml.Unmarshal(data, cfg); err != nil {
+		return nil, errors.Wrapf(err, "parse config %q", path)
+	}
+	if cfg.Dir, err = filepath.Abs(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+
+	for _, value := range []*string{&cfg.Dialect, &cfg.DSN, &cfg.Schema, &cfg.Package, &cfg.Migrations} {
+		*value = os.ExpandEnv(*value)
+	}
+	if cfg.Dialect == "" {
+		return nil, errors.Errorf("config %q: dialect is required", path)
+	}
+	if cfg.Migrations == "" {
+		cfg.Migrations = "migrations"
+	}
+	if !filepath.IsAbs(cfg.Migrations) {
+		cfg.Migrations = filepath.Join(cfg.Dir, cfg.Migrations)
+	}
+	return
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// mainDir is the directory, relative to config file, of generated main
+const mainDir = ".aorm"
+
+var mainTemplate = `// Code generated by aorm. DO NOT EDIT.
+
+package main
+
+import (
+	"github.com/moisespsena-go/aorm/cli"
+
+	_ %q
+)
+
+func main() {
+	cli.Main(true)
+}
+`
+
+// delegate runs the command line by the generated main that imports the config package, so its migrations and
+// models are registered.
+func (this *CLI) delegate(cfg *Config, args []string) (code int, err error) {
+	dir := filepath.Join(cfg.Dir, mainDir)
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+	if err = os.WriteFile(filepath.Join(dir, "main.go"), []byte(fmt.Sprintf(mainTemplate, cfg.Package)), 0644); err != nil {
+		return
+	}
+
+	cmd := exec.Command("go", append([]string{"run", "./" + mainDir, "-config", this.configPath}, args...)...)
+	cmd.Dir = cfg.Dir
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, this.Stdout, this.Stderr
+	if err = cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode(), nil
+		}
+		return 1, errors.Wrap(err, "run generated main")
+	}
+	return 0, nil
+}
@@ -0,0 +1,75 @@
+// Command aorm-reverse generates the aorm models of existing database tables.
+//
+//	aorm-reverse -dialect postgres -dsn "postgres://localhost/app?sslmode=disable" -package models -out models/models.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/moisespsena-go/aorm"
+	_ "github.com/moisespsena-go/aorm/dialects/postgres"
+	_ "github.com/moisespsena-go/aorm/dialects/sqlite"
+	"github.com/moisespsena-go/aorm/reverse"
+)
+
+func main() {
+	var (
+		dialect = flag.String("dialect", "sqlite3", "database dialect: sqlite3 or postgres")
+		dsn     = flag.String("dsn", "", "data source name")
+		schema  = flag.String("schema", "", "database schema (postgres only, defaults to public)")
+		pkg     = flag.String("package", "models", "package name of generated source")
+		tables  = flag.String("tables", "", "comma separated table names (defaults to all tables)")
+		out     = flag.String("out", "", "output file (defaults to stdout)")
+	)
+	flag.Parse()
+
+	if *dsn == "" {
+		fmt.Fprintln(os.Stderr, "aorm-reverse: -dsn is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := run(*dialect, *dsn, *schema, *pkg, *tables, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "aorm-reverse:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dialect, dsn, schema, pkg, tables, out string) (err error) {
+	db, err := aorm.Open(dialect, dsn)
+	if err != nil {
+		return
+	}
+	defer db.Close()
+
+	var names []string
+	for _, name := range strings.Split(tables, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	result, err := reverse.Inspect(db, schema, names...)
+	if err != nil {
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		var f *os.File
+		if f, err = os.Create(out); err != nil {
+			return
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+	return reverse.Generate(w, pkg, result)
+}
@@ -0,0 +1,8 @@
+// Command aorm runs the migrations and inspects the database schema. See the cli package for usage.
+package main
+
+import "github.com/moisespsena-go/aorm/cli"
+
+func main() {
+	cli.Main(false)
+}
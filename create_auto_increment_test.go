@@ -0,0 +1,45 @@
+package aorm_test
+
+import (
+	"testing"
+)
+
+type AutoIncrementItem struct {
+	ID   int64 `sql:"primary_key;auto_increment"`
+	Name string
+}
+
+type IntIDItem struct {
+	ID   int
+	Name string
+}
+
+func TestCreateBlankAutoIncrementPrimaryKey(t *testing.T) {
+	DB.DropTableIfExists(&AutoIncrementItem{}, &IntIDItem{})
+	DB.AutoMigrate(&AutoIncrementItem{}, &IntIDItem{})
+
+	a, b := AutoIncrementItem{Name: "a"}, AutoIncrementItem{Name: "b"}
+	for _, item := range []*AutoIncrementItem{&a, &b} {
+		if err := DB.Create(item).Error; err != nil {
+			t.Fatalf("no error should happen when create with blank auto increment primary key, but got %v", err)
+		}
+	}
+	if a.ID == 0 || b.ID == 0 || a.ID == b.ID {
+		t.Errorf("primary keys should be assigned by database, but got %v and %v", a.ID, b.ID)
+	}
+
+	c, d := IntIDItem{Name: "c"}, IntIDItem{Name: "d"}
+	for _, item := range []*IntIDItem{&c, &d} {
+		if err := DB.Create(item).Error; err != nil {
+			t.Fatalf("no error should happen when create with blank int primary key, but got %v", err)
+		}
+	}
+	if c.ID == 0 || d.ID == 0 || c.ID == d.ID {
+		t.Errorf("primary keys should be assigned by database, but got %v and %v", c.ID, d.ID)
+	}
+
+	e := IntIDItem{ID: 10, Name: "e"}
+	if err := DB.Create(&e).Error; err != nil || e.ID != 10 {
+		t.Errorf("given primary key should be inserted, but got %v, %v", e.ID, err)
+	}
+}
@@ -0,0 +1,131 @@
+package aorm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type searchCTE struct {
+	name      string
+	query     *Query
+	recursive bool
+}
+
+// subQueryOf returns the query of value, used as sub query. Accepts `*DB`, `*Query`, `Query` or `string`.
+func subQueryOf(value interface{}) (*Query, error) {
+	switch t := value.(type) {
+	case *DB:
+		return t.QueryExpr(), nil
+	case *Query:
+		return t, nil
+	case Query:
+		return &t, nil
+	case string:
+		return &Query{Query: t}, nil
+	default:
+		return nil, errors.Errorf("invalid sub query type %T", value)
+	}
+}
+
+// With add common table expression `WITH name AS (query)`. The name accepts column list, e.g. `"totals (user_id, total)"`.
+// The query accepts `*DB`, `*Query` or `string`.
+//
+//	db.With("paid", db.Model(&Order{}).Select("user_id").Where("paid")).Where("id IN (SELECT user_id FROM paid)").Find(&users)
+func (s *DB) With(name string, query interface{}) *DB {
+	return s.with(name, query, false)
+}
+
+// WithRecursive add recursive common table expression `WITH RECURSIVE name AS (query)`.
+func (s *DB) WithRecursive(name string, query interface{}) *DB {
+	return s.with(name, query, true)
+}
+
+func (s *DB) with(name string, query interface{}, recursive bool) *DB {
+	q, err := subQueryOf(query)
+	if err != nil {
+		s = s.clone()
+		s.AddError(err)
+		return s
+	}
+	return s.clone().search.With(&searchCTE{name, q, recursive}).db
+}
+
+// FromSubQuery select from sub query aliased as alias, instead of model table.
+//
+//	db.FromSubQuery(db.Model(&User{}).Where("age > ?", 18), "adults").Where("adults.name LIKE ?", "j%").Find(&users)
+func (s *DB) FromSubQuery(query interface{}, alias string) *DB {
+	q, err := subQueryOf(query)
+	if err != nil {
+		s = s.clone()
+		s.AddError(err)
+		return s
+	}
+	return s.clone().search.FromQuery(q, alias).db
+}
+
+// WhereExists filter records where the sub query returns any row: `WHERE EXISTS (query)`
+func (s *DB) WhereExists(query interface{}) *DB {
+	return s.whereSubQuery("EXISTS", query)
+}
+
+// WhereNotExists filter records where the sub query does not returns rows: `WHERE NOT EXISTS (query)`
+func (s *DB) WhereNotExists(query interface{}) *DB {
+	return s.whereSubQuery("NOT EXISTS", query)
+}
+
+// WhereIn filter records where column value is in sub query results: `WHERE column IN (query)`
+func (s *DB) WhereIn(column string, query interface{}) *DB {
+	return s.whereSubQuery(QuoteIfPossible(s.Dialect(), column)+" IN", query)
+}
+
+// WhereNotIn filter records where column value is not in sub query results: `WHERE column NOT IN (query)`
+func (s *DB) WhereNotIn(column string, query interface{}) *DB {
+	return s.whereSubQuery(QuoteIfPossible(s.Dialect(), column)+" NOT IN", query)
+}
+
+func (s *DB) whereSubQuery(operator string, query interface{}) *DB {
+	q, err := subQueryOf(query)
+	if err != nil {
+		s = s.clone()
+		s.AddError(err)
+		return s
+	}
+	return s.Where(operator+" ?", q.Wrap("(", ")"))
+}
+
+// withSQL returns the `WITH` prefix of query. The sub queries args are appended to scope vars, so it must be
+// called before other query parts, to keep the bind vars order.
+func (scope *Scope) withSQL() string {
+	var (
+		ctes      []string
+		recursive bool
+	)
+
+	for _, cte := range scope.Search.ctes {
+		q, err := cte.query.Build(scope)
+		if err != nil {
+			scope.Err(errors.Wrapf(err, "build CTE %q", cte.name))
+			return ""
+		}
+		ctes = append(ctes, fmt.Sprintf("%v AS (%v)", scope.quoteIfPossible(cte.name), q))
+		recursive = recursive || cte.recursive
+	}
+
+	if cte := scope.treeCTE(); cte != "" {
+		ctes = append(ctes, cte)
+		recursive = true
+	}
+
+	if len(ctes) == 0 {
+		return ""
+	}
+
+	var prefix = "WITH "
+	// mssql does not uses RECURSIVE keyword
+	if recursive && scope.Dialect().GetName() != "mssql" {
+		prefix += "RECURSIVE "
+	}
+	return prefix + strings.Join(ctes, ", ") + " "
+}
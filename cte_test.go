@@ -0,0 +1,47 @@
+package aorm_test
+
+import (
+	"fmt"
+	"testing"
+)
+
+type CTEUser struct {
+	ID   int
+	Name string
+	Age  int64
+}
+
+func (CTEUser) TableName() string {
+	return "cte_users"
+}
+
+func TestQueryBuilderCTEAndSubQueries(t *testing.T) {
+	DB.DropTableIfExists(&CTEUser{})
+	DB.AutoMigrate(&CTEUser{})
+	for i, age := range []int64{20, 30, 40} {
+		DB.Save(&CTEUser{Name: fmt.Sprintf("query_cte_user%d", i+1), Age: age})
+	}
+
+	var users []CTEUser
+	DB.With("adults", DB.Model(&CTEUser{}).Select("id").Where("name LIKE ? AND age > ?", "query_cte_%", 25)).
+		Where("id IN (SELECT id FROM adults)").Where("age < ?", 40).Find(&users)
+	if len(users) != 1 || users[0].Name != "query_cte_user2" {
+		t.Errorf("One user should be found with CTE, instead found %d", len(users))
+	}
+
+	users = nil
+	DB.FromSubQuery(DB.Model(&CTEUser{}).Where("name LIKE ?", "query_cte_%"), "cte_sub").
+		Where("cte_sub.age >= ?", 30).Find(&users)
+	if len(users) != 2 {
+		t.Errorf("Two users should be found from sub query, instead found %d", len(users))
+	}
+
+	users = nil
+	DB.Where("name LIKE ?", "query_cte_%").
+		WhereIn("id", DB.Model(&CTEUser{}).Select("id").Where("age > ?", 25)).
+		WhereNotExists("SELECT 1 FROM cte_users u2 WHERE u2.id = cte_users.id AND u2.age > 35").
+		Find(&users)
+	if len(users) != 1 || users[0].Name != "query_cte_user2" {
+		t.Errorf("One user should be found with sub query conditions, instead found %d", len(users))
+	}
+}
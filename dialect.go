@@ -26,6 +26,19 @@ func newDialect(name string, db SQLCommon) (dialect Dialector) {
 	return commontDialect
 }
 
+// cloneDialect returns a copy of dialect bound to db, so the dialect of parent db is not changed
+func cloneDialect(dialect Dialector, db SQLCommon) Dialector {
+	value := reflect.ValueOf(dialect)
+	if value.Kind() != reflect.Ptr {
+		return newDialect(dialect.GetName(), db)
+	}
+	clone := reflect.New(value.Elem().Type())
+	clone.Elem().Set(value.Elem())
+	dialect = clone.Interface().(Dialector)
+	dialect.SetDB(db)
+	return dialect
+}
+
 // RegisterDialect register new dialect
 func RegisterDialect(name string, dialect Dialector) {
 	dialectsMap[name] = dialect
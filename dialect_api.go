@@ -54,6 +54,15 @@ type Dialector interface {
 	RegisterAssigner(assigner ...Assigner)
 	GetAssigner(typ reflect.Type) (assigner Assigner)
 	DuplicateUniqueIndexError(indexes IndexMap, tableName string, sqlErr error) (err error)
+	// IsRetryableError returns if the transaction failed by serialization failure or deadlock, so it can be retried
+	IsRetryableError(err error) bool
+	// MaxBindVars returns the maximum number of bind variables of a statement
+	MaxBindVars() int
+	// SkipLockedSQL returns the locking clause of SELECT that skips the rows locked by other transactions, or
+	// blank if not supported
+	SkipLockedSQL() string
+	// GeneratedColumnSQL returns the definition of column computed by database from expr
+	GeneratedColumnSQL(dataType, expr string, stored bool) string
 	ZeroValueOf(typ reflect.Type) string
 	BytesToSql(b []byte) string
 
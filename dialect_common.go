@@ -201,6 +201,25 @@ func (d commonDialect) DuplicateUniqueIndexError(_ IndexMap, _ string, sqlErr er
 	return sqlErr
 }
 
+func (commonDialect) IsRetryableError(error) bool {
+	return false
+}
+
+func (commonDialect) MaxBindVars() int {
+	return 999
+}
+
+func (commonDialect) SkipLockedSQL() string {
+	return ""
+}
+
+func (commonDialect) GeneratedColumnSQL(dataType, expr string, stored bool) string {
+	if stored {
+		return dataType + " GENERATED ALWAYS AS (" + expr + ") STORED"
+	}
+	return dataType + " GENERATED ALWAYS AS (" + expr + ") VIRTUAL"
+}
+
 func (commonDialect) ZeroValueOf(typ reflect.Type) string {
 	switch typ.Kind() {
 	case reflect.String:
@@ -195,6 +195,21 @@ func (mysql) DefaultValueStr() string {
 	return "VALUES()"
 }
 
+// IsRetryableError returns if err is a deadlock (1213) or lock wait timeout (1205)
+func (mysql) IsRetryableError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "Error 1213") || strings.Contains(msg, "Error 1205")
+}
+
+func (mysql) MaxBindVars() int {
+	return 65535
+}
+
+// SkipLockedSQL returns the SKIP LOCKED clause, supported since MySQL 8.0
+func (mysql) SkipLockedSQL() string {
+	return "FOR UPDATE SKIP LOCKED"
+}
+
 func (d mysql) DuplicateUniqueIndexError(indexes IndexMap, tableName string, sqlErr error) (err error) {
 	msg := sqlErr.Error()
 	if strings.Contains(msg, "Duplicate entry") && msg[len(msg)-1] == '\'' {
@@ -108,12 +108,31 @@ func (postgres) BytesToSql(b []byte) string {
 	return `'\x` + hex.EncodeToString(b) + `'`
 }
 
+// schemaOf splits the table name, that may be qualified with schema, into the schema condition and table name
+func (postgres) schemaOf(tableName string, argIndex int) (cond string, args []interface{}, table string) {
+	schema, table := SplitSchemaTableName(tableName)
+	if schema == "" {
+		return "CURRENT_SCHEMA()", nil, table
+	}
+	return "$" + strconv.Itoa(argIndex), []interface{}{schema}, table
+}
+
 func (this postgres) HasIndex(tableName string, indexName string) bool {
 	var count int
-	this.db.QueryRow("SELECT count(*) FROM pg_indexes WHERE tablename = $1 AND indexname = $2 AND schemaname = CURRENT_SCHEMA()", tableName, indexName).Scan(&count)
+	schema, args, tableName := this.schemaOf(tableName, 3)
+	this.db.QueryRow("SELECT count(*) FROM pg_indexes WHERE tablename = $1 AND indexname = $2 AND schemaname = "+schema,
+		append([]interface{}{tableName, indexName}, args...)...).Scan(&count)
 	return count > 0
 }
 
+func (this postgres) RemoveIndex(tableName string, indexName string) error {
+	if schema, _ := SplitSchemaTableName(tableName); schema != "" {
+		indexName = Quote(this, schema) + "." + indexName
+	}
+	_, err := this.db.Exec(fmt.Sprintf("DROP INDEX %v", indexName))
+	return err
+}
+
 func (this postgres) HasForeignKey(tableName string, foreignKeyName string) bool {
 	var count int
 	this.db.QueryRow("SELECT count(con.conname) FROM pg_constraint con WHERE $1::regclass::oid = con.conrelid AND con.conname = $2 AND con.contype='f'", tableName, foreignKeyName).Scan(&count)
@@ -122,13 +141,17 @@ func (this postgres) HasForeignKey(tableName string, foreignKeyName string) bool
 
 func (this postgres) HasTable(tableName string) bool {
 	var count int
-	this.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1 AND table_type = 'BASE TABLE' AND table_schema = CURRENT_SCHEMA()", tableName).Scan(&count)
+	schema, args, tableName := this.schemaOf(tableName, 2)
+	this.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.tables WHERE table_name = $1 AND table_type = 'BASE TABLE' AND table_schema = "+schema,
+		append([]interface{}{tableName}, args...)...).Scan(&count)
 	return count > 0
 }
 
 func (this postgres) HasColumn(tableName string, columnName string) bool {
 	var count int
-	this.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2 AND table_schema = CURRENT_SCHEMA()", tableName, columnName).Scan(&count)
+	schema, args, tableName := this.schemaOf(tableName, 3)
+	this.db.QueryRow("SELECT count(*) FROM INFORMATION_SCHEMA.columns WHERE table_name = $1 AND column_name = $2 AND table_schema = "+schema,
+		append([]interface{}{tableName, columnName}, args...)...).Scan(&count)
 	return count > 0
 }
 
@@ -158,6 +181,31 @@ func (this postgres) DuplicateUniqueIndexError(indexes IndexMap, tableName strin
 	return sqlErr
 }
 
+// IsRetryableError returns if err is a serialization failure (40001) or deadlock (40P01)
+func (postgres) IsRetryableError(err error) bool {
+	switch SQLStateOf(err) {
+	case "40001", "40P01":
+		return true
+	case "":
+		msg := err.Error()
+		return strings.Contains(msg, "could not serialize access") || strings.Contains(msg, "deadlock detected")
+	}
+	return false
+}
+
+func (postgres) MaxBindVars() int {
+	return 65535
+}
+
+func (postgres) SkipLockedSQL() string {
+	return "FOR UPDATE SKIP LOCKED"
+}
+
+// GeneratedColumnSQL returns the STORED generated column, because postgres does not support VIRTUAL
+func (postgres) GeneratedColumnSQL(dataType, expr string, stored bool) string {
+	return dataType + " GENERATED ALWAYS AS (" + expr + ") STORED"
+}
+
 func (this postgres) Init() {
 	if this.db == nil {
 		return
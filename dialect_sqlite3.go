@@ -111,6 +111,17 @@ func (s sqlite3) CurrentDatabase() (name string) {
 	return
 }
 
+// IsRetryableError returns if err is SQLITE_BUSY or SQLITE_LOCKED
+func (sqlite3) IsRetryableError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "database is locked") || strings.Contains(msg, "database table is locked")
+}
+
+// MaxBindVars returns the SQLITE_MAX_VARIABLE_NUMBER default of versions before 3.32.0
+func (sqlite3) MaxBindVars() int {
+	return 999
+}
+
 func (s sqlite3) DuplicateUniqueIndexError(indexes IndexMap, _ string, sqlErr error) (err error) {
 	msg := sqlErr.Error()
 	if strings.Contains(msg, "UNIQUE constraint failed") {
@@ -74,6 +74,28 @@ func (s mssql) DuplicateUniqueIndexError(indexes aorm.IndexMap, tableName string
 	panic("implement me")
 }
 
+// IsRetryableError returns if err is a deadlock (1205)
+func (mssql) IsRetryableError(err error) bool {
+	return strings.Contains(err.Error(), "deadlock victim")
+}
+
+func (mssql) MaxBindVars() int {
+	return 2100
+}
+
+// SkipLockedSQL returns blank, because mssql uses the READPAST table hint instead of locking clause
+func (mssql) SkipLockedSQL() string {
+	return ""
+}
+
+// GeneratedColumnSQL returns the computed column, that is PERSISTED if stored. The type is inferred from expr.
+func (mssql) GeneratedColumnSQL(dataType, expr string, stored bool) string {
+	if stored {
+		return "AS (" + expr + ") PERSISTED"
+	}
+	return "AS (" + expr + ")"
+}
+
 func (mssql) GetName() string {
 	return "mssql"
 }
@@ -2,7 +2,6 @@ package postgres
 
 import (
 	"fmt"
-	"regexp"
 	"time"
 
 	"github.com/moisespsena-go/bid"
@@ -24,16 +23,51 @@ type TableDatePartitionSpec struct {
 	From, To time.Time
 }
 
+// SpecOf returns the partition spec of period that contains t
+func (this DatePartitionType) SpecOf(t time.Time) TableDatePartitionSpec {
+	var (
+		y, m, d = t.UTC().Date()
+		start   time.Time
+	)
+	switch this {
+	case DatePartitionDaily:
+		start = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+		return TableDatePartitionSpec{fmt.Sprintf("y%04dm%02dd%02d", y, m, d), start, start.AddDate(0, 0, 1)}
+	case DatePartitionMonthlyDiv2:
+		start = time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+		if d > 15 {
+			return TableDatePartitionSpec{fmt.Sprintf("y%04dm%02dmw2", y, m), start.AddDate(0, 0, 15), start.AddDate(0, 1, 0)}
+		}
+		return TableDatePartitionSpec{fmt.Sprintf("y%04dm%02dmw1", y, m), start, start.AddDate(0, 0, 15)}
+	case DatePartitionMonthly:
+		start = time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+		return TableDatePartitionSpec{fmt.Sprintf("y%04dm%02d", y, m), start, start.AddDate(0, 1, 0)}
+	default:
+		start = time.Date(y, 1, 1, 0, 0, 0, 0, time.UTC)
+		return TableDatePartitionSpec{fmt.Sprintf("y%04d", y), start, start.AddDate(1, 0, 0)}
+	}
+}
+
+// TableName returns the partition table name. The parent table may be qualified with schema.
 func (this TableDatePartitionSpec) TableName(parentTable string) string {
+	_, parentTable = aorm.SplitSchemaTableName(parentTable)
 	return parentTable + "_" + this.Name
 }
 
+// CreateSQL returns the create sql of partition in schema. If schema is blank, uses the schema of parent table,
+// or `public`.
 func (this TableDatePartitionSpec) CreateSQL(parentTable, schema string) string {
+	return this.createSQL(parentTable, schema, "2006-01-02")
+}
+
+func (this TableDatePartitionSpec) createSQL(parentTable, schema, layout string) string {
 	if schema == "" {
-		schema = "public"
+		if schema, _ = aorm.SplitSchemaTableName(parentTable); schema == "" {
+			schema = "public"
+		}
 	}
-	return `CREATE TABLE IF NOT EXISTS "` + schema + `"."` + this.TableName(parentTable) + `" PARTITION OF "` + parentTable + `"` +
-		` FOR VALUES FROM ('` + this.From.Format("2006-01-02") + `') TO ('` + this.To.Format("2006-01-02") + `')`
+	return `CREATE TABLE IF NOT EXISTS "` + schema + `"."` + this.TableName(parentTable) + `" PARTITION OF ` + quoteTable(parentTable) +
+		` FOR VALUES FROM ('` + this.From.Format(layout) + `') TO ('` + this.To.Format(layout) + `')`
 }
 
 func TablePartitionsByBid2Weeks(values ...bid.BID) (partitions []TableDatePartitionSpec) {
@@ -128,37 +162,7 @@ func TablePartitionsByDay(values ...bid.BID) (partitions []TableDatePartitionSpe
 	return
 }
 
-func BidPartition(model *aorm.ModelStruct, typ DatePartitionType, schema string) {
-	model.Indexes["!"+model.PrimaryField().Name] = &aorm.StructIndex{
-		Fields: model.PrimaryFields,
-	}
-	model.CreateTable(aorm.Before, func(data *aorm.TypeCallbackData) {
-		data.Scope.Query.Query = regexp.MustCompile(`(?ims),\s*primary\s+key\s+\([^)]+\)\s*`).ReplaceAllString(data.Scope.Query.Query, "")
-		data.Scope.Query.Query += fmt.Sprintf(" PARTITION BY RANGE (public.pgbid_get_utc_date(%s))", model.PrimaryField().DBName)
-	})
-	model.BeforeCreate(aorm.Before, func(data *aorm.ScopeCallbackData) {
-		pk := data.Scope.PrimaryKey().(bid.BID)
-		var partition TableDatePartitionSpec
-		switch typ {
-		case DatePartitionDaily:
-			partition = TablePartitionsByDay(pk)[0]
-		case DatePartitionMonthlyDiv2:
-			partition = TablePartitionsByBid2Weeks(pk)[0]
-		case DatePartitionMonthly:
-			partition = TablePartitionsByMonth(pk)[0]
-		default:
-			partition = TablePartitionsByYear(pk)[0]
-		}
-		if schema != "" && schema != "public" {
-			if _, err := data.Scope.SQLDB().Exec(`CREATE SCHEMA IF NOT EXISTS "` + schema + `"`); err != nil {
-				data.Scope.Err(err)
-				return
-			}
-		}
-		mainTbName := data.Scope.TableName()
-		query := partition.CreateSQL(mainTbName, schema)
-		if _, err := data.Scope.SQLDB().Exec(query); err != nil {
-			data.Scope.Err(err)
-		}
-	})
+// BidPartition partitions model by date of primary key bid in schema, see Partition
+func BidPartition(model *aorm.ModelStruct, typ DatePartitionType, schema string) error {
+	return Partition(model, DatePartition{Type: typ, Schema: schema})
 }
@@ -0,0 +1,302 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/moisespsena-go/bid"
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+var (
+	partitionPrimaryKeyRegexp = regexp.MustCompile(`(?ims),\s*primary\s+key\s+\([^)]+\)\s*`)
+	partitionBoundToRegexp    = regexp.MustCompile(`(?i)\bTO\s+\('([^']+)'\)`)
+
+	bidType  = reflect.TypeOf(bid.BID{})
+	timeType = reflect.TypeOf(time.Time{})
+
+	// partitionedModels is the map of *aorm.ModelStruct to *partitioned
+	partitionedModels sync.Map
+)
+
+// DatePartition is the date range partitioning declaration of model.
+//
+//	postgres.Partition(aorm.StructOf(&Event{}), postgres.DatePartition{
+//		Field: "CreatedAt", Type: postgres.DatePartitionMonthly, Premake: 2, Retention: 12,
+//	})
+type DatePartition struct {
+	// Field is the name of partition key field, a bid.BID or time.Time. Defaults to primary field.
+	Field string
+	// Type is the period of each partition
+	Type DatePartitionType
+	// Schema is the schema of partitions. Defaults to schema of parent table.
+	Schema string
+	// Premake is the number of upcoming partitions, besides the current, created by MaintainPartitions
+	Premake int
+	// Retention is the number of past partitions, besides the current, kept by MaintainPartitions. The older
+	// partitions are dropped, or detached if Detach is true. Zero keeps all partitions.
+	Retention int
+	// Detach detaches the retired partitions instead of drop them
+	Detach bool
+}
+
+type partitioned struct {
+	DatePartition
+	model *aorm.ModelStruct
+	field *aorm.StructField
+	isBid bool
+
+	mu sync.RWMutex
+	// covered is the range [from, to) of partitions created by MaintainPartitions, by parent table
+	covered map[string][2]time.Time
+}
+
+// Partition declares the date range partitioning of model. The create table of model creates the partitioned
+// parent, MaintainPartitions creates the current and upcoming partitions, and inserts create the partition of
+// row on demand, if it is out of range of partitions created by MaintainPartitions.
+//
+// Partitions by bid.BID fields uses the date of bid as key, and the primary key constraint is replaced by an
+// index, because postgres does not support unique constraints over expressions. Partitions by time.Time fields
+// adds the field to the primary key.
+func Partition(model *aorm.ModelStruct, spec DatePartition) error {
+	p := &partitioned{DatePartition: spec, model: model, covered: map[string][2]time.Time{}}
+	if spec.Field == "" {
+		p.field = model.PrimaryField()
+	} else {
+		p.field, _ = model.FieldByName(spec.Field)
+	}
+	if p.field == nil || !p.field.IsNormal {
+		return errors.Errorf("postgres partition: invalid partition field %q of %s", spec.Field, model.Type)
+	}
+
+	switch typ := p.field.Struct.Type; {
+	case typ == bidType:
+		p.isBid = true
+		model.Indexes["!"+model.PrimaryField().Name] = &aorm.StructIndex{
+			Fields: model.PrimaryFields,
+		}
+	case typ == timeType, typ.Kind() == reflect.Ptr && typ.Elem() == timeType:
+	default:
+		return errors.Errorf("postgres partition: field %s.%s must be bid.BID or time.Time", model.Type, p.field.Name)
+	}
+
+	model.CreateTable(aorm.Before, p.beforeCreateTable)
+	model.BeforeCreate(aorm.Before, p.beforeCreate)
+	partitionedModels.Store(model, p)
+	return nil
+}
+
+// MaintainPartitions creates the current and upcoming partitions, and retires the partitions past retention,
+// of partitioned models at now. If models is empty, maintains all partitioned models. It should run
+// periodically, at least once per period of partitions.
+func MaintainPartitions(db *aorm.DB, now time.Time, models ...interface{}) (err error) {
+	var targets []*partitioned
+	if len(models) == 0 {
+		partitionedModels.Range(func(_, value interface{}) bool {
+			targets = append(targets, value.(*partitioned))
+			return true
+		})
+	} else {
+		for _, model := range models {
+			ms := db.StructOf(model)
+			value, ok := partitionedModels.Load(ms)
+			if !ok {
+				return errors.Errorf("postgres partition: %s is not partitioned", ms.Type)
+			}
+			targets = append(targets, value.(*partitioned))
+		}
+	}
+
+	for _, p := range targets {
+		if err = p.maintain(db, now); err != nil {
+			return errors.Wrapf(err, "postgres partition: maintain %s", p.model.Type)
+		}
+	}
+	return
+}
+
+func (this *partitioned) parentTable(scope *aorm.Scope) string {
+	return scope.QualifyTableName(scope.TableName())
+}
+
+func (this *partitioned) layout() string {
+	if this.isBid {
+		return "2006-01-02"
+	}
+	return "2006-01-02 15:04:05-07"
+}
+
+func (this *partitioned) create(db aorm.SQLCommon, parentTable string, spec TableDatePartitionSpec) (err error) {
+	if this.Schema != "" && this.Schema != "public" {
+		if _, err = db.Exec(`CREATE SCHEMA IF NOT EXISTS "` + this.Schema + `"`); err != nil {
+			return
+		}
+	}
+	_, err = db.Exec(spec.createSQL(parentTable, this.Schema, this.layout()))
+	return
+}
+
+func (this *partitioned) beforeCreateTable(data *aorm.TypeCallbackData) {
+	var (
+		query  = data.Scope.Query.Query
+		column = quoteTable(this.field.DBName)
+	)
+
+	if this.isBid {
+		query = partitionPrimaryKeyRegexp.ReplaceAllString(query, "")
+		column = "public.pgbid_get_utc_date(" + column + ")"
+	} else if !this.field.IsPrimaryKey {
+		var primaryKeys []string
+		for _, f := range this.model.PrimaryFields {
+			primaryKeys = append(primaryKeys, quoteTable(f.DBName))
+		}
+		primaryKeys = append(primaryKeys, column)
+		query = partitionPrimaryKeyRegexp.ReplaceAllString(query, ", PRIMARY KEY ("+strings.Join(primaryKeys, ",")+")")
+	}
+
+	data.Scope.Query.Query = query + " PARTITION BY RANGE (" + column + ")"
+}
+
+func (this *partitioned) beforeCreate(data *aorm.ScopeCallbackData) {
+	field, ok := data.Scope.FieldByName(this.field.Name)
+	if !ok {
+		return
+	}
+	fieldValue := reflect.Indirect(field.Field)
+	if !fieldValue.IsValid() {
+		return
+	}
+
+	var t time.Time
+	switch value := fieldValue.Interface().(type) {
+	case bid.BID:
+		if len(value) == 0 {
+			return
+		}
+		t = value.Time()
+	case time.Time:
+		t = value
+	}
+	if t.IsZero() {
+		return
+	}
+
+	parentTable := this.parentTable(data.Scope)
+	if this.isCovered(parentTable, t) {
+		return
+	}
+	data.Scope.Err(this.create(data.Scope.SQLDB(), parentTable, this.Type.SpecOf(t)))
+}
+
+func (this *partitioned) isCovered(parentTable string, t time.Time) bool {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+	r, ok := this.covered[parentTable]
+	return ok && !t.Before(r[0]) && t.Before(r[1])
+}
+
+func (this *partitioned) maintain(db *aorm.DB, now time.Time) (err error) {
+	var (
+		scope       = db.NewModelScope(this.model, this.model.Value)
+		parentTable = this.parentTable(scope)
+		current     = this.Type.SpecOf(now)
+		spec        = current
+	)
+
+	if !db.Dialect().HasTable(parentTable) {
+		return
+	}
+
+	for i := 0; i <= this.Premake; i++ {
+		if err = this.create(db.CommonDB(), parentTable, spec); err != nil {
+			return
+		}
+		spec = this.Type.SpecOf(spec.To)
+	}
+
+	this.mu.Lock()
+	this.covered[parentTable] = [2]time.Time{current.From, spec.From}
+	this.mu.Unlock()
+
+	if this.Retention > 0 {
+		cutoff := current.From
+		for i := 0; i < this.Retention; i++ {
+			cutoff = this.Type.SpecOf(cutoff.Add(-time.Nanosecond)).From
+		}
+		err = this.retire(db.CommonDB(), parentTable, cutoff)
+	}
+	return
+}
+
+// retire detaches or drops the partitions of parent table with upper bound until cutoff
+func (this *partitioned) retire(db aorm.SQLCommon, parentTable string, cutoff time.Time) (err error) {
+	var (
+		rows       *sql.Rows
+		partitions []string
+	)
+	if rows, err = db.Query(`SELECT n.nspname, c.relname, pg_get_expr(c.relpartbound, c.oid) FROM pg_inherits i
+	JOIN pg_class c ON c.oid = i.inhrelid
+	JOIN pg_namespace n ON n.oid = c.relnamespace
+	WHERE i.inhparent = $1::regclass`, parentTable); err != nil {
+		return
+	}
+
+	for rows.Next() {
+		var schema, name, bound string
+		if err = rows.Scan(&schema, &name, &bound); err != nil {
+			rows.Close()
+			return
+		}
+		match := partitionBoundToRegexp.FindStringSubmatch(bound)
+		if match == nil {
+			// DEFAULT or MAXVALUE partition
+			continue
+		}
+		var to time.Time
+		if to, err = parsePartitionBound(match[1]); err != nil {
+			rows.Close()
+			return
+		}
+		if !to.After(cutoff) {
+			partitions = append(partitions, quoteTable(schema)+"."+quoteTable(name))
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for _, partition := range partitions {
+		var query string
+		if this.Detach {
+			query = fmt.Sprintf("ALTER TABLE %v DETACH PARTITION %v", quoteTable(parentTable), partition)
+		} else {
+			query = fmt.Sprintf("DROP TABLE %v", partition)
+		}
+		if _, err = db.Exec(query); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func parsePartitionBound(value string) (t time.Time, err error) {
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05-07", "2006-01-02 15:04:05-07:00", "2006-01-02 15:04:05"} {
+		if t, err = time.Parse(layout, value); err == nil {
+			return
+		}
+	}
+	return t, errors.Errorf("invalid partition bound %q", value)
+}
+
+// quoteTable quotes the table name, that may be qualified with schema
+func quoteTable(name string) string {
+	return aorm.QuotePath(aorm.QuoteRuner('"'), name)
+}
@@ -21,6 +21,8 @@ var (
 	ErrUnaddressable = errors.New("using unaddressable value")
 	// ErrSingleUpdateKey single UPDATE require primary key value
 	ErrSingleUpdateKey = errors.New("Single UPDATE require primary key value.")
+	// ErrStoredGeneratedColumn sqlite can't add STORED generated column to existing table, it must be recreated
+	ErrStoredGeneratedColumn = errors.New("can't add STORED generated column to existing table")
 
 	IsError     = error_utils.IsError
 	ErrorByType = error_utils.ErrorByType
@@ -0,0 +1,25 @@
+package aorm_test
+
+import (
+	"testing"
+)
+
+func TestExec(t *testing.T) {
+	DB.Exec("DROP TABLE IF EXISTS exec_items")
+	if err := DB.Exec("CREATE TABLE exec_items (id integer, name varchar(10))").Error; err != nil {
+		t.Fatalf("no error should happen when exec without model, but got %v", err)
+	}
+
+	if err := DB.Exec("INSERT INTO exec_items (id, name) VALUES (?, ?), (?, ?)", 1, "a", 2, "b").Error; err != nil {
+		t.Fatalf("no error should happen when exec with args, but got %v", err)
+	}
+
+	if db := DB.Exec("UPDATE exec_items SET name = ? WHERE id IN (?)", "c", []int{1, 2}); db.Error != nil || db.RowsAffected != 2 {
+		t.Errorf("should update 2 rows with slice arg, but got %v rows, %v", db.RowsAffected, db.Error)
+	}
+
+	var count int
+	if err := DB.DB().QueryRow("SELECT COUNT(*) FROM exec_items WHERE name = 'c'").Scan(&count); err != nil || count != 2 {
+		t.Errorf("2 rows should be updated, but got %v, %v", count, err)
+	}
+}
@@ -6,11 +6,7 @@ var fakeDBMap sync.Map
 
 // CreateFakeDB create a new fake db with dialect
 func CreateFakeDB(dialect string) (db *DB) {
-	db = &DB{
-		values:  map[interface{}]interface{}{},
-		dialect: newDialect(dialect, nil),
-	}
-	db.parent = db
+	db = NewDB(newDialect(dialect, nil))
 	fakeDBMap.Store(dialect, db)
 	return
 }
@@ -0,0 +1,245 @@
+package aorm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// FullTextColumn is the name of the postgres generated tsvector column
+	FullTextColumn = "search_vector"
+	// FullTextDefaultConfig is the default postgres text search configuration
+	FullTextDefaultConfig = "simple"
+)
+
+// fullTextWeights is the rank weight of each postgres weight label, the same of `ts_rank` defaults
+var fullTextWeights = map[string]string{"A": "1.0", "B": "0.4", "C": "0.2", "D": "0.1"}
+
+// FullTextField is the field tagged with `sql:"FULLTEXT"`, or `sql:"FULLTEXT:B"` to weight it with label A, B, C or D.
+type FullTextField struct {
+	*StructField
+	Weight string
+}
+
+// FullText is the full text search document of model, composed by all FULLTEXT fields.
+// The postgres text search configuration is set by `sql:"FULLTEXT_CONFIG:english"` tag in any of the fields.
+type FullText struct {
+	Fields []*FullTextField
+	Config string
+}
+
+func (this *ModelStruct) setupFullText() {
+	var ft = &FullText{Config: FullTextDefaultConfig}
+	for _, field := range this.Fields {
+		if !field.IsNormal {
+			continue
+		}
+		weight, ok := field.TagSettings.GetOk("FULLTEXT")
+		if !ok {
+			continue
+		}
+		if _, ok := fullTextWeights[weight]; !ok {
+			weight = "A"
+		}
+		if config := field.TagSettings.Get("FULLTEXT_CONFIG"); config != "" {
+			ft.Config = config
+		}
+		ft.Fields = append(ft.Fields, &FullTextField{field, weight})
+	}
+	if len(ft.Fields) > 0 {
+		this.FullText = ft
+	}
+}
+
+// autoFullText creates the full text search structures of model: a generated tsvector column and GIN index on
+// postgres, a FULLTEXT index on mysql, and a FTS5 table kept in sync by triggers on sqlite.
+func (scope *Scope) autoFullText() *Scope {
+	if scope.modelStruct.FullText == nil || scope.HasError() {
+		return scope
+	}
+	var (
+		statements = scope.fullTextSQL()
+		exists     = make([]bool, len(statements))
+	)
+	for i, stmt := range statements {
+		exists[i] = stmt.exists != nil && stmt.exists()
+	}
+	for i, stmt := range statements {
+		if !exists[i] && scope.Raw(stmt.sql).Exec().HasError() {
+			break
+		}
+	}
+	return scope
+}
+
+type fullTextStatement struct {
+	sql    string
+	exists func() bool
+}
+
+// fullTextSQL returns the statements that creates the full text search structures of model
+func (scope *Scope) fullTextSQL() (statements []fullTextStatement) {
+	var (
+		ft              = scope.modelStruct.FullText
+		tableName       = scope.QualifyTableName(scope.TableName())
+		quotedTableName = scope.QuotedQualifiedTableName()
+		columns         []string
+		add             = func(exists func() bool, sql ...string) {
+			for _, sql := range sql {
+				statements = append(statements, fullTextStatement{sql, exists})
+			}
+		}
+	)
+	for _, f := range ft.Fields {
+		columns = append(columns, scope.Quote(f.DBName))
+	}
+
+	switch scope.Dialect().GetName() {
+	case "postgres":
+		var vectors []string
+		for _, f := range ft.Fields {
+			vectors = append(vectors, fmt.Sprintf("setweight(to_tsvector('%s', coalesce(%v, '')), '%s')", ft.Config, scope.Quote(f.DBName), f.Weight))
+		}
+		add(func() bool {
+			return scope.Dialect().HasColumn(tableName, FullTextColumn)
+		}, fmt.Sprintf("ALTER TABLE %v ADD %v tsvector GENERATED ALWAYS AS (%v) STORED",
+			quotedTableName, scope.Quote(FullTextColumn), strings.Join(vectors, " || ")))
+
+		indexName := "ix_" + scope.TableName() + "_" + FullTextColumn
+		add(func() bool {
+			return scope.Dialect().HasIndex(tableName, indexName)
+		}, fmt.Sprintf("CREATE INDEX %v ON %v USING GIN (%v)", scope.Quote(indexName), quotedTableName, scope.Quote(FullTextColumn)))
+	case "mysql":
+		indexName := "ft_" + scope.TableName()
+		add(func() bool {
+			return scope.Dialect().HasIndex(tableName, indexName)
+		}, fmt.Sprintf("CREATE FULLTEXT INDEX %v ON %v (%v)", scope.Quote(indexName), quotedTableName, strings.Join(columns, ", ")))
+	case "sqlite3":
+		var (
+			ftsTable       = scope.TableName() + "_fts"
+			quotedFtsTable = scope.Quote(ftsTable)
+			newColumns     = "new." + strings.Join(columns, ", new.")
+			oldColumns     = "old." + strings.Join(columns, ", old.")
+			insert         = fmt.Sprintf("INSERT INTO %v(rowid, %v) VALUES (new.rowid, %v);", quotedFtsTable, strings.Join(columns, ", "), newColumns)
+			remove         = fmt.Sprintf("INSERT INTO %v(%v, rowid, %v) VALUES ('delete', old.rowid, %v);", quotedFtsTable, quotedFtsTable, strings.Join(columns, ", "), oldColumns)
+		)
+		add(func() bool {
+			return scope.Dialect().HasTable(ftsTable)
+		},
+			fmt.Sprintf("CREATE VIRTUAL TABLE %v USING fts5(%v, content=%v)", quotedFtsTable, strings.Join(columns, ", "), scope.Quote(scope.TableName())),
+			fmt.Sprintf("CREATE TRIGGER %v AFTER INSERT ON %v BEGIN %v END", scope.Quote(ftsTable+"_ai"), quotedTableName, insert),
+			fmt.Sprintf("CREATE TRIGGER %v AFTER DELETE ON %v BEGIN %v END", scope.Quote(ftsTable+"_ad"), quotedTableName, remove),
+			fmt.Sprintf("CREATE TRIGGER %v AFTER UPDATE ON %v BEGIN %v %v END", scope.Quote(ftsTable+"_au"), quotedTableName, remove, insert),
+			fmt.Sprintf("INSERT INTO %v(%v) VALUES ('rebuild')", quotedFtsTable, quotedFtsTable),
+		)
+	}
+	return
+}
+
+// Search filters the records matching the full text search terms over the FULLTEXT fields of model. If rank
+// is given, the relevance of each record is selected as extra select value of key and column rank:
+//
+//	db.Search("red shoes", "rank").Order("rank DESC").Find(&products)
+func (s *DB) Search(terms string, rank ...string) *DB {
+	db := s.Where(&fullTextMatch{terms})
+	if len(rank) > 0 && rank[0] != "" {
+		db = db.ExtraSelect(rank[0], []interface{}{float64(0)}, &fullTextRank{terms, rank[0]})
+	}
+	return db
+}
+
+type fullTextMatch struct {
+	terms string
+}
+
+// WhereClause implements WhereClauser
+func (this *fullTextMatch) WhereClause(scope *Scope) (result Query) {
+	ft := scope.fullText()
+	if ft == nil {
+		return
+	}
+	var quotedTableName = scope.QuotedTableName()
+
+	switch scope.Dialect().GetName() {
+	case "postgres":
+		result.Query = fmt.Sprintf("%v.%v @@ websearch_to_tsquery('%s', ?)", quotedTableName, scope.Quote(FullTextColumn), ft.Config)
+	case "mysql":
+		result.Query = scope.fullTextMySQLMatch(ft)
+	case "sqlite3":
+		ftsTable := scope.Quote(scope.TableName() + "_fts")
+		result.Query = fmt.Sprintf("%v.rowid IN (SELECT rowid FROM %v WHERE %v MATCH ?)", quotedTableName, ftsTable, ftsTable)
+		result.AddArgs(fts5Terms(this.terms))
+		return
+	default:
+		scope.Err(errors.Errorf("full text search is not supported by %q dialect", scope.Dialect().GetName()))
+		return
+	}
+	result.AddArgs(this.terms)
+	return
+}
+
+type fullTextRank struct {
+	terms string
+	name  string
+}
+
+// WhereClause implements WhereClauser
+func (this *fullTextRank) WhereClause(scope *Scope) (result Query) {
+	ft := scope.fullText()
+	if ft == nil {
+		return
+	}
+	var quotedTableName = scope.QuotedTableName()
+
+	switch scope.Dialect().GetName() {
+	case "postgres":
+		result.Query = fmt.Sprintf("ts_rank(%v.%v, websearch_to_tsquery('%s', ?))", quotedTableName, scope.Quote(FullTextColumn), ft.Config)
+		result.AddArgs(this.terms)
+	case "mysql":
+		result.Query = scope.fullTextMySQLMatch(ft)
+		result.AddArgs(this.terms)
+	case "sqlite3":
+		var (
+			ftsTable = scope.Quote(scope.TableName() + "_fts")
+			weights  []string
+		)
+		for _, f := range ft.Fields {
+			weights = append(weights, fullTextWeights[f.Weight])
+		}
+		result.Query = fmt.Sprintf("(SELECT -bm25(%v, %v) FROM %v WHERE %v MATCH ? AND %v.rowid = %v.rowid)",
+			ftsTable, strings.Join(weights, ", "), ftsTable, ftsTable, ftsTable, quotedTableName)
+		result.AddArgs(fts5Terms(this.terms))
+	default:
+		scope.Err(errors.Errorf("full text search is not supported by %q dialect", scope.Dialect().GetName()))
+		return
+	}
+	result.Query += " AS " + scope.Quote(this.name)
+	return
+}
+
+func (scope *Scope) fullText() *FullText {
+	ft := scope.Struct().FullText
+	if ft == nil {
+		scope.Err(errors.Errorf("full text search: %s has not FULLTEXT fields", scope.Struct().Type))
+	}
+	return ft
+}
+
+func (scope *Scope) fullTextMySQLMatch(ft *FullText) string {
+	var columns []string
+	for _, f := range ft.Fields {
+		columns = append(columns, scope.QuotedTableName()+"."+scope.Quote(f.DBName))
+	}
+	return fmt.Sprintf("MATCH (%v) AGAINST (? IN NATURAL LANGUAGE MODE)", strings.Join(columns, ", "))
+}
+
+// fts5Terms quotes each term as FTS5 string, so the terms match all words without FTS5 query syntax
+func fts5Terms(terms string) string {
+	var words = strings.Fields(terms)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"`
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,58 @@
+package aorm_test
+
+import (
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type FullTextArticle struct {
+	ID    int
+	Title string `sql:"FULLTEXT:A"`
+	Body  string `sql:"FULLTEXT:B;type:text"`
+	aorm.ExtraSelectModel
+}
+
+func TestFullTextSearch(t *testing.T) {
+	switch DB.Dialect().GetName() {
+	case "mssql":
+		t.Skip("mssql does not support full text search")
+	case "sqlite3":
+		// FTS5 is available only if sqlite is built with it, e.g. `go test -tags sqlite_fts5`
+		if err := DB.Exec("CREATE VIRTUAL TABLE aorm_fts5_probe USING fts5(body)").Error; err != nil {
+			t.Skipf("sqlite does not support FTS5: %v", err)
+		}
+		DB.Exec("DROP TABLE aorm_fts5_probe")
+	}
+
+	DB.DropTableIfExists(&FullTextArticle{})
+	if err := DB.AutoMigrate(&FullTextArticle{}).Error; err != nil {
+		t.Fatalf("no error should happen when migrate, but got %v", err)
+	}
+
+	DB.Save(&FullTextArticle{Title: "Gardening tips", Body: "How to grow tomatoes"})
+	DB.Save(&FullTextArticle{Title: "Tomatoes", Body: "Tomatoes recipes for the summer"})
+	DB.Save(&FullTextArticle{Title: "Databases", Body: "Full text search with sql"})
+
+	var articles []FullTextArticle
+	if err := DB.Search("tomatoes", "rank").Order("rank DESC").Find(&articles).Error; err != nil {
+		t.Fatalf("no error should happen when search, but got %v", err)
+	}
+	if len(articles) != 2 {
+		t.Fatalf("should find 2 articles, but got %v", len(articles))
+	}
+	if articles[0].Title != "Tomatoes" {
+		t.Errorf("article with matched title should be ranked first, but got %q", articles[0].Title)
+	}
+	if _, ok := articles[0].GetAormExtraScannedValue("rank"); !ok {
+		t.Errorf("rank should be selected")
+	}
+
+	// updates are indexed
+	DB.Model(&articles[1]).Update("Body", "How to grow peppers")
+	articles = nil
+	DB.Search("tomatoes").Find(&articles)
+	if len(articles) != 1 {
+		t.Errorf("should find 1 article after update, but got %v", len(articles))
+	}
+}
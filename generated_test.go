@@ -0,0 +1,81 @@
+package aorm_test
+
+import (
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type GeneratedLine struct {
+	ID    int64 `sql:"primary_key;auto_increment"`
+	Price int
+	Qty   int
+	Total int `sql:"GENERATED:price * qty;STORED"`
+}
+
+func TestGeneratedColumns(t *testing.T) {
+	DB.DropTableIfExists(&GeneratedLine{})
+	if err := DB.AutoMigrate(&GeneratedLine{}).Error; err != nil {
+		t.Fatalf("no error should happen when create table with generated column, but got %v", err)
+	}
+
+	field, _ := aorm.StructOf(&GeneratedLine{}).FieldByName("Total")
+	if field.Generated == nil || field.Generated.Expr != "price * qty" || !field.Generated.Stored {
+		t.Fatalf("generated column should be parsed from tag, but got %+v", field.Generated)
+	}
+
+	line := GeneratedLine{Price: 3, Qty: 2, Total: 100}
+	if err := DB.Save(&line).Error; err != nil {
+		t.Fatalf("generated column should not be written on create, but got %v", err)
+	}
+	if line.Total != 6 {
+		t.Errorf("generated column should be reloaded after create, but got %v", line.Total)
+	}
+
+	if err := DB.Model(&line).Update("qty", 5).Error; err != nil {
+		t.Fatalf("no error should happen when update, but got %v", err)
+	}
+	if line.Total != 15 {
+		t.Errorf("generated column should be reloaded after update, but got %v", line.Total)
+	}
+
+	line.Price, line.Total = 1, 0
+	if err := DB.Save(&line).Error; err != nil {
+		t.Fatalf("generated column should not be written on update, but got %v", err)
+	}
+	var found GeneratedLine
+	DB.First(&found, line.ID)
+	if found.Total != 5 || line.Total != 5 {
+		t.Errorf("generated column should be computed by database, but got %v and %v", found.Total, line.Total)
+	}
+}
+
+type GeneratedItem struct {
+	ID    int64 `sql:"primary_key;auto_increment"`
+	Price int
+}
+
+func (GeneratedItem) TableName() string {
+	return "generated_items"
+}
+
+type GeneratedItemStored struct {
+	GeneratedItem
+	Double int `sql:"GENERATED:price * 2;STORED"`
+}
+
+func TestGeneratedColumnsAddStored(t *testing.T) {
+	DB.DropTableIfExists(&GeneratedItem{})
+	if err := DB.AutoMigrate(&GeneratedItem{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	err := DB.AutoMigrate(&GeneratedItemStored{}).Error
+	if DB.Dialect().GetName() == "sqlite3" {
+		if !aorm.IsError(aorm.ErrStoredGeneratedColumn, err) {
+			t.Errorf("should not add stored generated column to existing sqlite table, but got %v", err)
+		}
+	} else if err != nil {
+		t.Errorf("no error should happen when add stored generated column, but got %v", err)
+	}
+}
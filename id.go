@@ -135,6 +135,10 @@ func (this Id) Exclude() ID {
 	return this
 }
 
+func (this Id) excluded() bool {
+	return this.exclude
+}
+
 type idSliceScoper struct {
 	exclude bool
 	values  []ID
@@ -144,11 +148,15 @@ func (this idSliceScoper) Values() []ID {
 	return this.values
 }
 
-func (this idSliceScoper) Exclude() idSliceScoper {
+func (this idSliceScoper) Exclude() IDSlicer {
 	this.exclude = true
 	return this
 }
 
+func (this idSliceScoper) excluded() bool {
+	return this.exclude
+}
+
 func (this idSliceScoper) WhereClause(scope *Scope) (result Query) {
 	tbName := scope.TableName() + "."
 	var q []string
@@ -184,11 +192,15 @@ func (this idSliceNamedTable) Values() []ID {
 	return this.values
 }
 
-func (this idSliceNamedTable) Exclude() idSliceNamedTable {
+func (this idSliceNamedTable) Exclude() IDSlicer {
 	this.exclude = true
 	return this
 }
 
+func (this idSliceNamedTable) excluded() bool {
+	return this.exclude
+}
+
 func (this idSliceNamedTable) WhereClause(scope *Scope) (result Query) {
 	var q []string
 	for _, id := range this.values {
@@ -231,6 +231,20 @@ type InlinePreloads struct {
 	Count uint
 	// map of field path -> alias_name
 	DBNames map[string]string
+	// paths of fields joined into the query, from the root scope
+	paths map[string]bool
+}
+
+func (c *InlinePreloads) addPath(path string) {
+	if c.paths == nil {
+		c.paths = map[string]bool{}
+	}
+	c.paths[path] = true
+}
+
+// Joined returns if the field of path, from the root scope, was joined into the query
+func (c *InlinePreloads) Joined(path string) bool {
+	return c != nil && c.paths[path]
 }
 
 func (c *InlinePreloads) Next(fieldPath ...string) string {
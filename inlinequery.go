@@ -50,7 +50,7 @@ func (this *FieldPathQuery) WhereClause(scope *Scope) Query {
 	query := this.query
 	tbName := scope.QuotedTableName()
 	for _, p := range this.paths {
-		if dbName, ok := scope.inlinePreloads.DBNames[p]; ok {
+		if dbName, ok := scope.inlinePreloadDBName(p); ok {
 			query = strings.ReplaceAll(query, "{"+p+"}", dbName)
 		} else if p == "" {
 			query = strings.ReplaceAll(query, "{}", tbName)
@@ -66,6 +66,14 @@ func (this *FieldPathQuery) WhereClause(scope *Scope) Query {
 	return Query{query, this.args}
 }
 
+// inlinePreloadDBName returns the db name of inline preloaded path, if scope is not inline preloaded returns false
+func (scope *Scope) inlinePreloadDBName(path string) (dbName string, ok bool) {
+	if scope.inlinePreloads != nil {
+		dbName, ok = scope.inlinePreloads.DBNames[path]
+	}
+	return
+}
+
 func (this *FieldPathQuery) String() string {
 	return this.query
 }
@@ -1,12 +1,19 @@
 package aorm
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 )
 
+// joinTablePayloadPrefix is the column alias prefix of join table payload columns selected on preload
+const joinTablePayloadPrefix = "aorm_jtp__"
+
+// joinTableForeignKeyPrefix is the column alias prefix of join table source foreign keys selected on preload
+const joinTableForeignKeyPrefix = "aorm_jtfk__"
+
 // JoinTableForeignKey join table foreign key struct
 type JoinTableForeignKey struct {
 	DBName           string
@@ -24,6 +31,24 @@ type JoinTableHandler struct {
 	tableNameFunc func(singular bool) string
 	source        JoinTableSource
 	destination   JoinTableSource
+	payload       *ModelStruct
+
+	polymorphicDBName string
+	polymorphicValue  func(ctx context.Context, singular bool) string
+}
+
+// JoinTablePayloadModel is an embeddable helper for many2many destination models that receives the
+// join table payload when preloaded
+type JoinTablePayloadModel struct {
+	JoinTablePayload interface{} `sql:"-"`
+}
+
+func (this *JoinTablePayloadModel) SetAormJoinTablePayload(payload interface{}) {
+	this.JoinTablePayload = payload
+}
+
+func (this *JoinTablePayloadModel) GetAormJoinTablePayload() interface{} {
+	return this.JoinTablePayload
 }
 
 func (this *JoinTableHandler) TableName(singular bool) string {
@@ -38,6 +63,36 @@ func (this *JoinTableHandler) Destination() JoinTableSource {
 	return this.destination
 }
 
+// SetPayload set the model struct stored as extra columns of each join table row
+func (this *JoinTableHandler) SetPayload(payload *ModelStruct) {
+	this.payload = payload
+}
+
+// Payload return the payload model struct, or nil if handler does not have payload
+func (this *JoinTableHandler) Payload() *ModelStruct {
+	return this.payload
+}
+
+// PayloadFields return the payload fields stored as join table columns
+func (this *JoinTableHandler) PayloadFields() (fields []*StructField) {
+	if this.payload == nil {
+		return
+	}
+	var fks = map[string]bool{}
+	for _, fk := range this.source.ForeignKeys {
+		fks[fk.DBName] = true
+	}
+	for _, fk := range this.destination.ForeignKeys {
+		fks[fk.DBName] = true
+	}
+	for _, field := range this.payload.Fields {
+		if field.IsNormal && !field.IsIgnored && !field.IsPrimaryKey && !fks[field.DBName] {
+			fields = append(fields, field)
+		}
+	}
+	return
+}
+
 // SourceForeignKeys return source foreign keys
 func (this *JoinTableHandler) SourceForeignKeys() []JoinTableForeignKey {
 	return this.source.ForeignKeys
@@ -51,6 +106,8 @@ func (this *JoinTableHandler) DestinationForeignKeys() []JoinTableForeignKey {
 // Setup initialize a default join table handler
 func (this *JoinTableHandler) Setup(relationship *Relationship, tableName func(singular bool) string, source, destination *ModelStruct) {
 	this.tableNameFunc = tableName
+	this.polymorphicDBName = relationship.PolymorphicDBName
+	this.polymorphicValue = relationship.PolymorphicValue
 	this.source = JoinTableSource{ModelStruct: source}
 	this.source.ForeignKeys = []JoinTableForeignKey{}
 	for idx, fieldName := range relationship.ForeignFieldNames {
@@ -75,6 +132,23 @@ func (this JoinTableHandler) Table(db *DB) string {
 	return this.TableName(db.singularTable)
 }
 
+// updatePolymorphicConditionMap set the owner type column value of polymorphic join tables
+func (this JoinTableHandler) updatePolymorphicConditionMap(conditionMap map[string]interface{}, db *DB) {
+	if this.polymorphicDBName != "" {
+		conditionMap[this.polymorphicDBName] = this.polymorphicValue(db.Context, db.singularTable)
+	}
+}
+
+// updateTenantConditionMap set the tenant column value of tenanted join tables. If stamp is false, the
+// tenant is not set when tenant is unscoped.
+func (this JoinTableHandler) updateTenantConditionMap(conditionMap map[string]interface{}, db *DB, stamp bool) {
+	if field := joinTableTenantField(&this); field != nil && (stamp || !db.GetBool(OptKeyTenantUnscoped)) {
+		if tenant, ok := db.GetTenant(); ok {
+			conditionMap[field.DBName] = RawOfId(tenant)
+		}
+	}
+}
+
 func (this JoinTableHandler) updateConditionMap(conditionMap map[string]interface{}, db *DB, joinTableSources []JoinTableSource, sources ...interface{}) {
 	for _, source := range sources {
 		instance := InstanceOf(source)
@@ -95,6 +169,12 @@ func (this JoinTableHandler) updateConditionMap(conditionMap map[string]interfac
 
 // Add create relationship in join table for source and destination
 func (this JoinTableHandler) Add(handler JoinTableHandlerInterface, db *DB, source interface{}, destination interface{}) (err error) {
+	return this.AddWith(handler, db, source, destination, nil)
+}
+
+// AddWith create relationship in join table for source and destination with payload values.
+// If relationship already exists, the payload columns are updated.
+func (this JoinTableHandler) AddWith(handler JoinTableHandlerInterface, db *DB, source, destination, payload interface{}) (err error) {
 	var (
 		scope        = db.NewScope("")
 		conditionMap = map[string]interface{}{}
@@ -106,21 +186,45 @@ func (this JoinTableHandler) Add(handler JoinTableHandlerInterface, db *DB, sour
 	// Update condition map for destination
 	this.updateConditionMap(conditionMap, db, []JoinTableSource{this.destination}, destination)
 
-	var assignColumns, binVars, conditions []string
-	var values []interface{}
+	// Update condition map for polymorphic owner type
+	this.updatePolymorphicConditionMap(conditionMap, db)
+
+	// Update condition map for tenant
+	this.updateTenantConditionMap(conditionMap, db, true)
+
+	var assignColumns, binVars, conditions, payloadAssigns []string
+	var values, conditionValues, payloadValues []interface{}
 	for key, value := range conditionMap {
 		assignColumns = append(assignColumns, scope.Quote(key))
 		binVars = append(binVars, `?`)
 		conditions = append(conditions, fmt.Sprintf("%v = ?", scope.Quote(key)))
-		values = append(values, value)
+		conditionValues = append(conditionValues, value)
 	}
 
-	for _, value := range values {
-		values = append(values, value)
+	values = append(values, conditionValues...)
+
+	if payload != nil {
+		if this.payload == nil {
+			return errors.New("join table handler does not have payload")
+		}
+		if indirectType(reflect.TypeOf(payload)) != this.payload.Type {
+			return fmt.Errorf("wrong payload type %T for join table handler", payload)
+		}
+		instance := InstanceOf(payload)
+		for _, field := range this.PayloadFields() {
+			f := instance.FieldsMap[field.Name]
+			assignColumns = append(assignColumns, scope.Quote(field.DBName))
+			binVars = append(binVars, `?`)
+			payloadAssigns = append(payloadAssigns, fmt.Sprintf("%v = ?", scope.Quote(field.DBName)))
+			payloadValues = append(payloadValues, f.Field.Interface())
+		}
+		values = append(values, payloadValues...)
 	}
 
+	values = append(values, conditionValues...)
+
 	tableName := handler.Table(db)
-	quotedTable := scope.Quote(tableName)
+	quotedTable := scope.Quote(scope.QualifyTableName(tableName))
 	sql := fmt.Sprintf(
 		"INSERT INTO %v (%v) SELECT %v %v WHERE NOT EXISTS (SELECT * FROM %v WHERE %v)",
 		quotedTable,
@@ -130,9 +234,22 @@ func (this JoinTableHandler) Add(handler JoinTableHandlerInterface, db *DB, sour
 		quotedTable,
 		strings.Join(conditions, " AND "),
 	)
-	if err = db.Table(tableName).Exec(sql, values...).Error; err != nil {
+	res := db.Table(tableName).Exec(sql, values...)
+	if err = res.Error; err != nil {
 		return NewQueryError(err, Query{sql, values}, db.dialect.BindVar)
 	}
+
+	if res.RowsAffected == 0 && len(payloadAssigns) > 0 {
+		values = append(payloadValues, conditionValues...)
+		sql = fmt.Sprintf("UPDATE %v SET %v WHERE %v",
+			quotedTable,
+			strings.Join(payloadAssigns, ","),
+			strings.Join(conditions, " AND "),
+		)
+		if err = db.Table(tableName).Exec(sql, values...).Error; err != nil {
+			return NewQueryError(err, Query{sql, values}, db.dialect.BindVar)
+		}
+	}
 	return
 }
 
@@ -146,6 +263,8 @@ func (this JoinTableHandler) Delete(handler JoinTableHandlerInterface, db *DB, s
 	)
 
 	this.updateConditionMap(conditionMap, db, []JoinTableSource{this.source, this.destination}, sources...)
+	this.updatePolymorphicConditionMap(conditionMap, db)
+	this.updateTenantConditionMap(conditionMap, db, false)
 
 	for key, value := range conditionMap {
 		conditions = append(conditions, fmt.Sprintf("%v = ?", scope.Quote(key)))
@@ -160,7 +279,12 @@ func (this JoinTableHandler) Delete(handler JoinTableHandlerInterface, db *DB, s
 
 // JoinWith query with `Join` conditions
 func (this JoinTableHandler) JoinWith(handler JoinTableHandlerInterface, db *DB, source interface{}) *DB {
-	if indirectType(this.source.ModelStruct.Type) == indirectType(reflect.TypeOf(source)) {
+	sourceType := indirectType(reflect.TypeOf(source))
+	if sourceType.Kind() == reflect.Slice {
+		// preload of many sources
+		sourceType = indirectType(sourceType.Elem())
+	}
+	if indirectType(this.source.ModelStruct.Type) == sourceType {
 		var (
 			scope           = db.NewModelScope(this.source.ModelStruct, source)
 			tableName       = handler.Table(db)
@@ -199,7 +323,20 @@ func (this JoinTableHandler) JoinWith(handler JoinTableHandlerInterface, db *DB,
 			condString = fmt.Sprintf("1 <> 1")
 		}
 
-		return db.Joins(fmt.Sprintf("INNER JOIN %v ON %v", quotedTableName, strings.Join(joinConditions, " AND "))).
+		var joinValues []interface{}
+		if this.polymorphicDBName != "" {
+			joinConditions = append(joinConditions, fmt.Sprintf("%v.%v = ?", quotedTableName, Quote(dialect, this.polymorphicDBName)))
+			joinValues = append(joinValues, this.polymorphicValue(db.Context, db.singularTable))
+		}
+
+		tenantMap := map[string]interface{}{}
+		this.updateTenantConditionMap(tenantMap, db, false)
+		for column, tenant := range tenantMap {
+			joinConditions = append(joinConditions, fmt.Sprintf("%v.%v = ?", quotedTableName, Quote(dialect, column)))
+			joinValues = append(joinValues, tenant)
+		}
+
+		return db.Joins(fmt.Sprintf("INNER JOIN %v ON %v", scope.Quote(scope.QualifyTableName(tableName)), strings.Join(joinConditions, " AND ")), joinValues...).
 			Where(condString, toQueryValues(foreignFieldValues)...)
 	}
 
@@ -23,3 +23,20 @@ type JoinTableHandlerInterface interface {
 	Destination() JoinTableSource
 	Copy() JoinTableHandlerInterface
 }
+
+// JoinTablePayloader is implemented by join table handlers that store extra payload columns on
+// each relationship row, besides the source and destination foreign keys
+type JoinTablePayloader interface {
+	// Payload return the payload model struct, or nil if handler does not have payload
+	Payload() *ModelStruct
+	// PayloadFields return the payload fields stored as join table columns
+	PayloadFields() []*StructField
+	// AddWith create relationship in join table for source and destination with payload values
+	AddWith(handler JoinTableHandlerInterface, db *DB, source, destination, payload interface{}) error
+}
+
+// JoinTablePayloadSetter is implemented by many2many destination models to receive the join table
+// payload when preloaded
+type JoinTablePayloadSetter interface {
+	SetAormJoinTablePayload(payload interface{})
+}
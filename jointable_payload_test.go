@@ -0,0 +1,60 @@
+package aorm_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type Membership struct {
+	Role     string
+	JoinedAt time.Time
+}
+
+type MemberGroup struct {
+	aorm.JoinTablePayloadModel
+	ID   int
+	Name string
+}
+
+type Member struct {
+	ID     int
+	Name   string
+	Groups []*MemberGroup `aorm:"many2many:member_groups_memberships;"`
+}
+
+func TestJoinTablePayload(t *testing.T) {
+	DB.Exec("drop table member_groups_memberships;")
+	DB.AutoMigrate(&Member{}, &MemberGroup{})
+	DB.SetJoinTablePayload(&Member{}, "Groups", &Membership{})
+	DB.AutoMigrate(&Member{})
+
+	if !DB.Dialect().HasColumn("member_groups_memberships", "role") {
+		t.Fatalf("join table should have payload column role")
+	}
+
+	member := &Member{Name: "member"}
+	DB.Save(member)
+	group := &MemberGroup{Name: "admins"}
+
+	association := DB.Model(member).Association("Groups").AppendWith(group, &Membership{Role: "owner", JoinedAt: time.Now()})
+	if err := association.Error(); err != nil {
+		t.Fatalf("no error should happen when append with payload, but got %v", err)
+	}
+
+	DB.Model(member).Association("Groups").AppendWith(group, &Membership{Role: "admin", JoinedAt: time.Now()})
+
+	var found Member
+	if err := DB.Preload("Groups").First(&found, member.ID).Error; err != nil {
+		t.Fatalf("no error should happen when preload, but got %v", err)
+	}
+
+	if len(found.Groups) != 1 {
+		t.Fatalf("should found one group, but got %v", len(found.Groups))
+	}
+
+	if payload, ok := found.Groups[0].JoinTablePayload.(*Membership); !ok || payload.Role != "admin" {
+		t.Errorf("group payload should be updated to admin role, but got %#v", found.Groups[0].JoinTablePayload)
+	}
+}
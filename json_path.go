@@ -0,0 +1,144 @@
+package aorm
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// JSONPathExpr is the expression of value at path of JSON column, rendered per dialect: `#>>` on postgres,
+// `JSON_EXTRACT` on mysql, `JSON_VALUE` on mssql and `json_extract` on other dialects.
+type JSONPathExpr struct {
+	field string
+	path  []string
+}
+
+// JSONPath creates the expression of value at dotted path of JSON field, or column. Numeric path
+// elements are array indexes.
+//
+//	db.Where(aorm.JSONPath("Settings", "theme.color").Eq("dark")).Find(&users)
+//	db.Where(aorm.JSONPath("Settings", "tags.0").Ne("old")).Find(&users)
+func JSONPath(field, path string) *JSONPathExpr {
+	return &JSONPathExpr{field: field, path: strings.Split(path, ".")}
+}
+
+func (this *JSONPathExpr) column(scope *Scope) string {
+	if field, ok := scope.Struct().FieldByName(this.field); ok && field.IsNormal {
+		return scope.QuotedTableName() + "." + scope.Quote(field.DBName)
+	}
+	return scope.Quote(this.field)
+}
+
+// WhereClause implements WhereClauser
+func (this *JSONPathExpr) WhereClause(scope *Scope) (result Query) {
+	column := this.column(scope)
+
+	if scope.Dialect().GetName() == "postgres" {
+		var path = make([]string, len(this.path))
+		for i, key := range this.path {
+			path[i] = `"` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+		}
+		result.Query = column + " #>> ?::text[]"
+		result.AddArgs("{" + strings.Join(path, ",") + "}")
+		return
+	}
+
+	var path = "$"
+	for _, key := range this.path {
+		if _, err := strconv.Atoi(key); err == nil {
+			path += "[" + key + "]"
+		} else {
+			path += `."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+		}
+	}
+	result.AddArgs(path)
+
+	switch scope.Dialect().GetName() {
+	case "mysql":
+		result.Query = "JSON_UNQUOTE(JSON_EXTRACT(" + column + ", ?))"
+	case "mssql":
+		result.Query = "JSON_VALUE(" + column + ", ?)"
+	default:
+		result.Query = "json_extract(" + column + ", ?)"
+	}
+	return
+}
+
+func (this *JSONPathExpr) cond(op string, value interface{}) *jsonPathCond {
+	return &jsonPathCond{this, op, value}
+}
+
+// Eq compares value at path is equals to value. If value is nil, checks value at path is null or missing.
+func (this *JSONPathExpr) Eq(value interface{}) WhereClauser {
+	if value == nil {
+		return this.IsNull()
+	}
+	return this.cond("=", value)
+}
+
+// Ne compares value at path is not equals to value. If value is nil, checks value at path is not null.
+func (this *JSONPathExpr) Ne(value interface{}) WhereClauser {
+	if value == nil {
+		return this.IsNotNull()
+	}
+	return this.cond("<>", value)
+}
+
+// Gt compares value at path is greater than value
+func (this *JSONPathExpr) Gt(value interface{}) WhereClauser {
+	return this.cond(">", value)
+}
+
+// Gte compares value at path is greater than or equals to value
+func (this *JSONPathExpr) Gte(value interface{}) WhereClauser {
+	return this.cond(">=", value)
+}
+
+// Lt compares value at path is less than value
+func (this *JSONPathExpr) Lt(value interface{}) WhereClauser {
+	return this.cond("<", value)
+}
+
+// Lte compares value at path is less than or equals to value
+func (this *JSONPathExpr) Lte(value interface{}) WhereClauser {
+	return this.cond("<=", value)
+}
+
+// IsNull checks value at path is null or missing
+func (this *JSONPathExpr) IsNull() WhereClauser {
+	return this.cond("IS NULL", nil)
+}
+
+// IsNotNull checks value at path is not null
+func (this *JSONPathExpr) IsNotNull() WhereClauser {
+	return this.cond("IS NOT NULL", nil)
+}
+
+type jsonPathCond struct {
+	expr  *JSONPathExpr
+	op    string
+	value interface{}
+}
+
+// WhereClause implements WhereClauser
+func (this *jsonPathCond) WhereClause(scope *Scope) (result Query) {
+	result = this.expr.WhereClause(scope)
+	if this.op == "IS NULL" || this.op == "IS NOT NULL" {
+		result.Query += " " + this.op
+		return
+	}
+	value := this.value
+	if scope.Dialect().GetName() == "postgres" {
+		// `#>>` returns text, so compares with the text of JSON value
+		switch value.(type) {
+		case string, nil:
+		default:
+			if b, err := json.Marshal(value); err == nil {
+				value = string(b)
+			}
+		}
+	}
+	result.Query += " " + this.op + " ?"
+	result.AddArgs(value)
+	return
+}
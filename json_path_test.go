@@ -0,0 +1,70 @@
+package aorm_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+	"github.com/moisespsena-go/aorm/types"
+)
+
+type JSONSettings struct {
+	Theme struct {
+		Color string
+	}
+	Tags []string
+}
+
+type JSONUser struct {
+	ID       int
+	Name     string
+	Settings types.JSON[JSONSettings]
+}
+
+func TestJSONPath(t *testing.T) {
+	DB.DropTableIfExists(&JSONUser{})
+	if err := DB.AutoMigrate(&JSONUser{}).Error; err != nil {
+		t.Fatalf("no error should happen when migrate, but got %v", err)
+	}
+
+	var dark, light JSONSettings
+	dark.Theme.Color, dark.Tags = "dark", []string{"a", "b"}
+	light.Theme.Color = "light"
+	DB.Save(&JSONUser{Name: "dark", Settings: types.NewJSON(dark)})
+	DB.Save(&JSONUser{Name: "light", Settings: types.NewJSON(light)})
+
+	var users []JSONUser
+	if err := DB.Where(aorm.JSONPath("Settings", "Theme.Color").Eq("dark")).Find(&users).Error; err != nil {
+		t.Fatalf("no error should happen when query json path, but got %v", err)
+	}
+	if len(users) != 1 || users[0].Name != "dark" {
+		t.Fatalf("should find user with dark theme, but got %v", users)
+	}
+	if !reflect.DeepEqual(users[0].Settings.Data, dark) {
+		t.Errorf("settings should be %v, but got %v", dark, users[0].Settings.Data)
+	}
+
+	users = nil
+	DB.Where(aorm.JSONPath("Settings", "Tags.1").Eq("b")).Find(&users)
+	if len(users) != 1 || users[0].Name != "dark" {
+		t.Errorf("should find user by json array index, but got %v", users)
+	}
+
+	users = nil
+	DB.Where(aorm.JSONPath("Settings", "Theme.Color").Ne("dark")).Find(&users)
+	if len(users) != 1 || users[0].Name != "light" {
+		t.Errorf("should find user with other theme, but got %v", users)
+	}
+
+	users = nil
+	DB.Where(aorm.JSONPath("Settings", "Tags.0").Eq(nil)).Find(&users)
+	if len(users) != 1 || users[0].Name != "light" {
+		t.Errorf("should find user without json array value, but got %v", users)
+	}
+
+	users = nil
+	DB.Where(aorm.JSONPath("Settings", "Tags.0").Ne(nil)).Find(&users)
+	if len(users) != 1 || users[0].Name != "dark" {
+		t.Errorf("should find user with json array value, but got %v", users)
+	}
+}
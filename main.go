@@ -37,6 +37,7 @@ type DB struct {
 	singularTable      bool
 	assigners          *AssignerRegistrator
 	modelStructStorage *ModelStructStorage
+	changes            *changeSubscribers
 	Context            context.Context
 	noExec             bool
 
@@ -58,6 +59,7 @@ func NewDB(dialect ...Dialector) (db *DB) {
 		callbacks:          DefaultCallback,
 		Context:            context.Background(),
 		modelStructStorage: modelStructStorage,
+		changes:            &changeSubscribers{},
 	}
 	db.parent = db
 	for _, db.dialect = range dialect {
@@ -371,7 +373,11 @@ func (s *DB) Scopes(funcs ...func(*DB) *DB) *DB {
 }
 
 // Unscoped return all record including deleted record, refer Soft Delete https://jinzhu.github.io/gorm/crud.html#soft-delete
-func (s *DB) Unscoped() *DB {
+// and ignoring the default scopes of model. If scopes names is given, only these default scopes are disabled.
+func (s *DB) Unscoped(scopes ...string) *DB {
+	if len(scopes) > 0 {
+		return s.Set(OptKeyUnscopedScopes, appendScopeNames(s, OptKeyUnscopedScopes, scopes))
+	}
 	return s.clone().search.unscoped().db
 }
 
@@ -603,8 +609,7 @@ func (s *DB) Exec(sql string, values ...interface{}) *DB {
 		scope.Err(err)
 		return scope.db
 	}
-	scope.Query.Query = q
-	return scope.Exec().db
+	return scope.Raw(q).Exec().db
 }
 
 // Model specify the model you would like to run db operations
@@ -648,27 +653,57 @@ func (s *DB) Debug() *DB {
 
 // Begin begin a transaction
 func (s *DB) Begin() *DB {
-	c := s.clone()
-	if db, ok := c.db.(sqlDb); ok && db != nil {
-		tx, err := db.Begin()
-		c.db = interface{}(tx).(SQLCommon)
+	c, err := s.begin(nil)
+	c.AddError(err)
+	return c
+}
+
+// BeginTx begin a transaction with options, e.g. the isolation level, bound to context of db
+func (s *DB) BeginTx(opts *sql.TxOptions) *DB {
+	c, err := s.begin(opts)
+	c.AddError(err)
+	return c
+}
 
-		c.dialect.SetDB(c.db)
-		c.AddError(err)
+func (s *DB) begin(opts *sql.TxOptions) (c *DB, err error) {
+	c = s.clone()
+	var tx *sql.Tx
+	if opts != nil {
+		db, ok := c.db.(sqlDbTx)
+		if !ok || db == nil {
+			return c, ErrCantStartTransaction
+		}
+		ctx := c.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		tx, err = db.BeginTx(ctx, opts)
+	} else if db, ok := c.db.(sqlDb); ok && db != nil {
+		tx, err = db.Begin()
 	} else {
-		c.AddError(ErrCantStartTransaction)
+		return c, ErrCantStartTransaction
 	}
-	return c
+	c.db = interface{}(tx).(SQLCommon)
+	c.dialect = cloneDialect(c.dialect, c.db)
+	if err == nil {
+		c.trackTxRecords(s)
+	}
+	return
 }
 
-// Commit commit a transaction
+// Commit commit a transaction, then calls the `AfterCommit` hooks of records changed by it, or the
+// `AfterRollback` hooks if commit fails
 func (s *DB) Commit() *DB {
 	var emptySQLTx *sql.Tx
 	if db, ok := s.db.(sqlTx); ok && db != nil && db != emptySQLTx {
 		if v, ok := s.values[OptKeyCommitDisabled]; ok && v.(bool) {
 			s.AddError(db.Rollback())
+			s.AddError(s.flushTxRecords(false))
+		} else if err := db.Commit(); err != nil {
+			s.AddError(err)
+			s.AddError(s.flushTxRecords(false))
 		} else {
-			s.AddError(db.Commit())
+			s.AddError(s.flushTxRecords(true))
 		}
 	} else {
 		s.AddError(ErrInvalidTransaction)
@@ -678,7 +713,14 @@ func (s *DB) Commit() *DB {
 
 // Transaction execute func `f` into transaction
 func (s *DB) Transaction(f func(db *DB) (err error)) (err error) {
-	s = s.Begin().Set("aorm:disable_scope_transaction", true)
+	return s.transaction(nil, f)
+}
+
+func (s *DB) transaction(opts *sql.TxOptions, f func(db *DB) (err error)) (err error) {
+	if s, err = s.begin(opts); err != nil {
+		return errors.Wrap(err, "begin transaction")
+	}
+	s = s.Set("aorm:disable_scope_transaction", true)
 	defer func() {
 		if r := recover(); r != nil {
 			s.Rollback()
@@ -713,11 +755,12 @@ func (s *DB) RawTransaction(f func(tx *sql.Tx) (err error)) (err error) {
 	return f(tx)
 }
 
-// Rollback rollback a transaction
+// Rollback rollback a transaction, then calls the `AfterRollback` hooks of records changed by it
 func (s *DB) Rollback() *DB {
 	var emptySQLTx *sql.Tx
 	if db, ok := s.db.(sqlTx); ok && db != nil && db != emptySQLTx {
 		s.AddError(db.Rollback())
+		s.AddError(s.flushTxRecords(false))
 	} else {
 		s.AddError(ErrInvalidTransaction)
 	}
@@ -761,8 +804,10 @@ func (s *DB) Preload(field string, options ...*InlinePreloadOptions) *DB {
 	return s.clone().search.Preload(field, options...).db
 }
 
-// Preload preload associations with given conditions
-//    db.Preload("Orders", "state NOT IN (?)", "cancelled").Find(&users)
+// InlinePreload preload belongs to and has one associations joined into the main query
+//    db.InlinePreload("Profile").Find(&users)
+// Many to many associations can't be joined into a single row, so they are loaded by Preload after query, also
+// in nested paths (e.g. "Profile.Languages"), where the joined parents are not loaded again.
 func (s *DB) InlinePreload(field string, options ...*InlinePreloadOptions) *DB {
 	return s.clone().search.InlinePreload(field, options...).db
 }
@@ -881,13 +926,29 @@ func (s *DB) SetJoinTableHandler(source interface{}, column string, handler Join
 			destination := s.StructOf(reflect.New(field.Struct.Type).Interface())
 			handler.Setup(field.Relationship, DefaultM2MNamer(field), sourceStruct, destination)
 			field.Relationship.JoinTableHandler = handler
-			if table := handler.Table(s); s.Dialect().HasTable(table) {
+			if table := handler.Table(s); s.Dialect().HasTable(s.NewScope(source).QualifyTableName(table)) {
 				s.Table(table).AutoMigrate(handler)
 			}
 		}
 	}
 }
 
+// SetJoinTablePayload set a model struct used as extra columns of the join table for a many2many relation.
+// The join table handler must implements `SetPayload(*ModelStruct)`, as the default JoinTableHandler does.
+func (s *DB) SetJoinTablePayload(source interface{}, column string, payload interface{}) {
+	sourceStruct := s.StructOf(source)
+	if field, ok := sourceStruct.FieldByName(column); ok && field.Relationship != nil && field.Relationship.JoinTableHandler != nil {
+		if setter, ok := field.Relationship.JoinTableHandler.(interface{ SetPayload(*ModelStruct) }); ok {
+			setter.SetPayload(s.StructOf(payload))
+			if table := field.Relationship.JoinTableHandler.Table(s); s.Dialect().HasTable(s.NewScope(source).QualifyTableName(table)) {
+				s.NewScope(source).createJoinTable(field)
+			}
+			return
+		}
+		s.AddError(fmt.Errorf("join table handler of %s.%s does not support payload", sourceStruct.Type, column))
+	}
+}
+
 // AddError add error to the db
 func (s *DB) AddError(err error) error {
 	if err != nil {
@@ -46,7 +46,7 @@ func (s *DB) HasTable(value interface{}) bool {
 		tableName = scope.TableName()
 	}
 
-	has := scope.Dialect().HasTable(tableName)
+	has := scope.Dialect().HasTable(scope.QualifyTableName(tableName))
 	s.AddError(scope.db.Error)
 	return has
 }
@@ -0,0 +1,115 @@
+// Package migrate provides the versioned migrations, applied in order of its IDs and recorded into the
+// `aorm_migrations` table.
+//
+//	func init() {
+//		migrate.Register(&migrate.Migration{
+//			ID: "20200102150405_create_users",
+//			Up: func(db *aorm.DB) error {
+//				return db.CreateTable(&User{}).Error
+//			},
+//			Down: func(db *aorm.DB) error {
+//				return db.DropTable(&User{}).Error
+//			},
+//		})
+//	}
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+// VersionsTable is the table of applied migrations
+const VersionsTable = "aorm_migrations"
+
+// Migration is the versioned migration. Up and Down receive the transaction with migrator, so they can
+// create and auto migrate tables.
+type Migration struct {
+	// ID is the unique and sortable identifier, e.g. `20200102150405_create_users`
+	ID   string
+	Up   func(db *aorm.DB) error
+	Down func(db *aorm.DB) error
+}
+
+// Version is the record of applied migration
+type Version struct {
+	ID        string    `sql:"primary_key;size:255"`
+	AppliedAt time.Time `sql:"not null"`
+}
+
+// TableName returns the VersionsTable
+func (Version) TableName() string {
+	return VersionsTable
+}
+
+// Registry holds the migrations and models of application
+type Registry struct {
+	mu         sync.Mutex
+	migrations map[string]*Migration
+	models     []interface{}
+}
+
+// Default is the registry used by the package functions
+var Default = &Registry{}
+
+// Register adds the migrations to registry. It panics if migration ID is blank or duplicated or Up is nil.
+func (this *Registry) Register(migrations ...*Migration) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.migrations == nil {
+		this.migrations = map[string]*Migration{}
+	}
+	for _, m := range migrations {
+		if m.ID == "" {
+			panic("migrate: blank migration ID")
+		}
+		if m.Up == nil {
+			panic(fmt.Sprintf("migrate: migration %q without Up function", m.ID))
+		}
+		if _, ok := this.migrations[m.ID]; ok {
+			panic(fmt.Sprintf("migrate: duplicated migration %q", m.ID))
+		}
+		this.migrations[m.ID] = m
+	}
+}
+
+// Migrations returns the registered migrations sorted by ID
+func (this *Registry) Migrations() (migrations []*Migration) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	for _, m := range this.migrations {
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].ID < migrations[j].ID
+	})
+	return
+}
+
+// RegisterModels adds the models used by schema dump and diff
+func (this *Registry) RegisterModels(models ...interface{}) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	this.models = append(this.models, models...)
+}
+
+// Models returns the registered models
+func (this *Registry) Models() []interface{} {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	return append([]interface{}{}, this.models...)
+}
+
+// Register adds the migrations to Default registry
+func Register(migrations ...*Migration) {
+	Default.Register(migrations...)
+}
+
+// RegisterModels adds the models to Default registry
+func RegisterModels(models ...interface{}) {
+	Default.RegisterModels(models...)
+}
@@ -0,0 +1,37 @@
+package migrate_test
+
+import (
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+	"github.com/moisespsena-go/aorm/migrate"
+)
+
+func TestRegistry(t *testing.T) {
+	var (
+		registry = &migrate.Registry{}
+		up       = func(db *aorm.DB) error { return nil }
+	)
+	registry.Register(&migrate.Migration{ID: "20200102000000_b", Up: up}, &migrate.Migration{ID: "20200101000000_a", Up: up})
+
+	migrations := registry.Migrations()
+	if len(migrations) != 2 || migrations[0].ID != "20200101000000_a" || migrations[1].ID != "20200102000000_b" {
+		t.Errorf("migrations should be sorted by ID, but got %v", migrations)
+	}
+
+	for _, m := range []*migrate.Migration{{ID: "20200101000000_a", Up: up}, {ID: "", Up: up}, {ID: "20200103000000_c"}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("register of invalid migration %q should panic", m.ID)
+				}
+			}()
+			registry.Register(m)
+		}()
+	}
+
+	registry.RegisterModels(&migrate.Version{})
+	if models := registry.Models(); len(models) != 1 {
+		t.Errorf("models should be registered, but got %v", models)
+	}
+}
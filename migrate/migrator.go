@@ -0,0 +1,158 @@
+package migrate
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+// Status is the state of migration
+type Status struct {
+	ID string
+	// Migration is nil if the applied migration is not registered
+	Migration *Migration
+	// AppliedAt is nil if the migration is pending
+	AppliedAt *time.Time
+}
+
+// Migrator applies and reverts the migrations
+type Migrator struct {
+	DB         *aorm.DB
+	Migrations []*Migration
+}
+
+// New creates a new migrator. If migrations is empty, uses the migrations of Default registry.
+func New(db *aorm.DB, migrations ...*Migration) *Migrator {
+	if len(migrations) == 0 {
+		migrations = Default.Migrations()
+	} else {
+		migrations = append([]*Migration{}, migrations...)
+		sort.Slice(migrations, func(i, j int) bool {
+			return migrations[i].ID < migrations[j].ID
+		})
+	}
+	return &Migrator{DB: db, Migrations: migrations}
+}
+
+// Init creates the VersionsTable if not exists
+func (this *Migrator) Init() error {
+	if this.DB.HasTable(VersionsTable) {
+		return nil
+	}
+	migrator := this.DB.Migrator()
+	if err := migrator.Db().CreateTable(&Version{}).Error; err != nil {
+		return errors.Wrap(err, "migrate: create versions table")
+	}
+	return errors.Wrap(migrator.Close(), "migrate: create versions table")
+}
+
+// run calls the migration function with the migrator of db, so it can create and migrate tables
+func run(db *aorm.DB, f func(db *aorm.DB) error) error {
+	migrator := db.Migrator()
+	if err := f(migrator.Db()); err != nil {
+		return err
+	}
+	return migrator.Close()
+}
+
+// Applied returns the applied migrations versions sorted by ID
+func (this *Migrator) Applied() (versions []Version, err error) {
+	if err = this.Init(); err != nil {
+		return
+	}
+	err = errors.Wrap(this.DB.Order("id").Find(&versions).Error, "migrate: load versions")
+	return
+}
+
+// Status returns the state of registered and applied migrations sorted by ID
+func (this *Migrator) Status() (status []*Status, err error) {
+	versions, err := this.Applied()
+	if err != nil {
+		return
+	}
+	var byID = map[string]*Status{}
+	for _, m := range this.Migrations {
+		s := &Status{ID: m.ID, Migration: m}
+		byID[m.ID] = s
+		status = append(status, s)
+	}
+	for i := range versions {
+		v := &versions[i]
+		s := byID[v.ID]
+		if s == nil {
+			s = &Status{ID: v.ID}
+			status = append(status, s)
+		}
+		s.AppliedAt = &v.AppliedAt
+	}
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].ID < status[j].ID
+	})
+	return
+}
+
+// Up applies the first n pending migrations, or all pending migrations if n <= 0. Each migration is applied in
+// its own transaction.
+func (this *Migrator) Up(n int) (applied []*Migration, err error) {
+	status, err := this.Status()
+	if err != nil {
+		return
+	}
+	for _, s := range status {
+		if s.AppliedAt != nil || s.Migration == nil {
+			continue
+		}
+		if n > 0 && len(applied) == n {
+			break
+		}
+		m := s.Migration
+		if err = this.DB.Transaction(func(db *aorm.DB) error {
+			if err := run(db, m.Up); err != nil {
+				return err
+			}
+			return db.Create(&Version{ID: m.ID, AppliedAt: aorm.NowFunc()}).Error
+		}); err != nil {
+			return applied, errors.Wrapf(err, "migrate: up %q", m.ID)
+		}
+		applied = append(applied, m)
+	}
+	return
+}
+
+// Down reverts the last n applied migrations, or all applied migrations if n <= 0. Each migration is reverted
+// in its own transaction.
+func (this *Migrator) Down(n int) (reverted []*Migration, err error) {
+	status, err := this.Status()
+	if err != nil {
+		return
+	}
+	for i := len(status) - 1; i >= 0; i-- {
+		s := status[i]
+		if s.AppliedAt == nil {
+			continue
+		}
+		if n > 0 && len(reverted) == n {
+			break
+		}
+		m := s.Migration
+		if m == nil {
+			return reverted, errors.Errorf("migrate: down %q: migration is not registered", s.ID)
+		}
+		if m.Down == nil {
+			return reverted, errors.Errorf("migrate: down %q: migration is irreversible", s.ID)
+		}
+		if err = this.DB.Transaction(func(db *aorm.DB) error {
+			if err := run(db, m.Down); err != nil {
+				return err
+			}
+			return db.Where("id = ?", m.ID).Delete(&Version{}).Error
+		}); err != nil {
+			return reverted, errors.Wrapf(err, "migrate: down %q", m.ID)
+		}
+		reverted = append(reverted, m)
+	}
+	return
+}
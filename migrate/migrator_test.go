@@ -0,0 +1,130 @@
+package migrate_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+	_ "github.com/moisespsena-go/aorm/dialects/sqlite"
+	"github.com/moisespsena-go/aorm/migrate"
+)
+
+type MigrateUser struct {
+	ID   int `sql:"primary_key;auto_increment"`
+	Name string
+}
+
+type MigratePost struct {
+	ID    int `sql:"primary_key;auto_increment"`
+	Title string
+}
+
+func openDB(t *testing.T) *aorm.DB {
+	db, err := aorm.Open("sqlite3", filepath.Join(os.TempDir(), "aorm_migrate.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.DropTableIfExists(migrate.VersionsTable, &MigrateUser{}, &MigratePost{})
+	return db
+}
+
+func statusOf(t *testing.T, m *migrate.Migrator) (applied, pending []string) {
+	status, err := m.Status()
+	if err != nil {
+		t.Fatalf("no error should happen when load status, but got %v", err)
+	}
+	for _, s := range status {
+		if s.AppliedAt != nil {
+			applied = append(applied, s.ID)
+		} else {
+			pending = append(pending, s.ID)
+		}
+	}
+	return
+}
+
+func TestMigrator(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	m := migrate.New(db, &migrate.Migration{
+		ID: "20200102000000_create_posts",
+		Up: func(db *aorm.DB) error {
+			return db.CreateTable(&MigratePost{}).Error
+		},
+		Down: func(db *aorm.DB) error {
+			return db.DropTable(&MigratePost{}).Error
+		},
+	}, &migrate.Migration{
+		ID: "20200101000000_create_users",
+		Up: func(db *aorm.DB) error {
+			return db.CreateTable(&MigrateUser{}).Error
+		},
+		Down: func(db *aorm.DB) error {
+			return db.DropTable(&MigrateUser{}).Error
+		},
+	})
+
+	if applied, err := m.Up(1); err != nil || len(applied) != 1 || applied[0].ID != "20200101000000_create_users" {
+		t.Fatalf("first migration should be applied, but got %v, %v", applied, err)
+	}
+	if !db.HasTable(&MigrateUser{}) || db.HasTable(&MigratePost{}) {
+		t.Errorf("only users table should be created")
+	}
+	if applied, pending := statusOf(t, m); len(applied) != 1 || len(pending) != 1 || pending[0] != "20200102000000_create_posts" {
+		t.Errorf("posts migration should be pending, but got %v, %v", applied, pending)
+	}
+
+	if applied, err := m.Up(0); err != nil || len(applied) != 1 {
+		t.Fatalf("pending migrations should be applied, but got %v, %v", applied, err)
+	}
+	if applied, err := m.Up(0); err != nil || len(applied) != 0 {
+		t.Errorf("applied migrations should not be applied again, but got %v, %v", applied, err)
+	}
+
+	if reverted, err := m.Down(1); err != nil || len(reverted) != 1 || reverted[0].ID != "20200102000000_create_posts" {
+		t.Fatalf("last migration should be reverted, but got %v, %v", reverted, err)
+	}
+	if !db.HasTable(&MigrateUser{}) || db.HasTable(&MigratePost{}) {
+		t.Errorf("posts table should be dropped")
+	}
+	if applied, pending := statusOf(t, m); len(applied) != 1 || len(pending) != 1 {
+		t.Errorf("posts migration should be pending after down, but got %v, %v", applied, pending)
+	}
+}
+
+func TestMigratorUnregistered(t *testing.T) {
+	db := openDB(t)
+	defer db.Close()
+
+	m := migrate.New(db, &migrate.Migration{
+		ID: "20200101000000_create_users",
+		Up: func(db *aorm.DB) error {
+			return db.CreateTable(&MigrateUser{}).Error
+		},
+		Down: func(db *aorm.DB) error {
+			return db.DropTable(&MigrateUser{}).Error
+		},
+	})
+	if err := m.Init(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&migrate.Version{ID: "20190101000000_removed", AppliedAt: aorm.NowFunc()}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Up(0); err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := m.Status()
+	if err != nil || len(status) != 2 || status[0].ID != "20190101000000_removed" || status[0].Migration != nil || status[0].AppliedAt == nil {
+		t.Errorf("unregistered applied migration should be in status, but got %v, %v", status, err)
+	}
+
+	reverted, err := m.Down(0)
+	if len(reverted) != 1 || err == nil || !strings.Contains(err.Error(), "not registered") {
+		t.Errorf("down should stop at unregistered migration, but got %v, %v", reverted, err)
+	}
+}
@@ -7,6 +7,7 @@ type Migrator struct {
 	failed       bool
 	postHandlers []func(db *DB) error
 	transaction  bool
+	models       []interface{}
 }
 
 func NewMigrator(db *DB) *Migrator {
@@ -39,6 +39,7 @@ type ModelStruct struct {
 	Fields                         []*StructField
 	RelatedFields                  []*StructField
 	ReadOnlyFields                 []*StructField
+	GeneratedFields                []*StructField
 	DynamicFieldsByName            map[string]*StructField
 	Type                           reflect.Type
 	PluralTableName                string
@@ -52,6 +53,7 @@ type ModelStruct struct {
 	softDelete                     bool
 	Indexes                        IndexMap
 	UniqueIndexes                  IndexMap
+	FullText                       *FullText
 	Children                       []*ModelStruct
 	ChildrenByName                 map[string]*ModelStruct
 	HasManyChildren                []*ModelStruct
@@ -59,6 +61,8 @@ type ModelStruct struct {
 	InlinePreloadFields            []string
 	ForeignKeys                    []*ForeignKey
 	Tags                           TagSetting
+	Scopes                         map[string]*ModelScope
+	DefaultScopes                  []*ModelScope
 }
 
 func (this *ModelStruct) PkgPath() string {
@@ -185,6 +185,21 @@ func (this *ScopeCallbacks) BeforeSave(pos CallbackPosition, f ...ScopeCallback)
 func (this *ScopeCallbacks) BeforeUpdate(pos CallbackPosition, f ...ScopeCallback) *ScopeCallbacks {
 	return this.ScopeCallbackName(pos, "BeforeUpdate", f...)
 }
+func (this *ScopeCallbacks) AfterCommit(pos CallbackPosition, f ...ScopeCallback) *ScopeCallbacks {
+	return this.ScopeCallbackName(pos, "AfterCommit", f...)
+}
+func (this *ScopeCallbacks) AfterCreateCommit(pos CallbackPosition, f ...ScopeCallback) *ScopeCallbacks {
+	return this.ScopeCallbackName(pos, "AfterCreateCommit", f...)
+}
+func (this *ScopeCallbacks) AfterUpdateCommit(pos CallbackPosition, f ...ScopeCallback) *ScopeCallbacks {
+	return this.ScopeCallbackName(pos, "AfterUpdateCommit", f...)
+}
+func (this *ScopeCallbacks) AfterDeleteCommit(pos CallbackPosition, f ...ScopeCallback) *ScopeCallbacks {
+	return this.ScopeCallbackName(pos, "AfterDeleteCommit", f...)
+}
+func (this *ScopeCallbacks) AfterRollback(pos CallbackPosition, f ...ScopeCallback) *ScopeCallbacks {
+	return this.ScopeCallbackName(pos, "AfterRollback", f...)
+}
 func (this *ScopeCallbacks) AfterScan(pos CallbackPosition, f ...ScopeCallback) *ScopeCallbacks {
 	return this.ScopeCallbackName(pos, "AfterScan", f...)
 }
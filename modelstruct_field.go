@@ -42,6 +42,14 @@ type StructField struct {
 	Selector        FieldSelector
 	SelectWraper    FieldSelectWraper
 	Flag            FieldFlag
+	Generated       *GeneratedColumn
+}
+
+// GeneratedColumn is the column computed by database from expression, declared by tag
+// `GENERATED:price * qty;STORED`. The column is VIRTUAL unless STORED flag is set, if dialect supports it.
+type GeneratedColumn struct {
+	Expr   string
+	Stored bool
 }
 
 func (this *StructField) String() string {
@@ -102,6 +102,8 @@ func (this *ForeignKey) Definition(scope *Scope) (def ForeignKeyDefinition) {
 			}
 		}
 	}
+	def.SrcTableName = scope.QualifyTableName(def.SrcTableName)
+	def.DstTableName = scope.QualifyTableName(def.DstTableName)
 	return
 }
 
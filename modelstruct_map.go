@@ -33,3 +33,13 @@ func (s *safeModelStructsMap) Get(key reflect.Type) *ModelStruct {
 func newModelStructsMap() *safeModelStructsMap {
 	return &safeModelStructsMap{l: new(sync.RWMutex), m: make(map[reflect.Type]*ModelStruct)}
 }
+
+// Values returns all model structs
+func (s *safeModelStructsMap) Values() (values []*ModelStruct) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+	for _, value := range s.m {
+		values = append(values, value)
+	}
+	return
+}
@@ -136,6 +136,33 @@ func (this *ModelStruct) setup(pth []string, embedded bool, from *ModelStruct) (
 					tbNamer = DefaultM2MNamer(field)
 				}
 
+				// Post has many tags through taggings, tag polymorphic is Taggable, then join table
+				// use taggable_id and taggable_type ('posts') as source foreign keys
+				var sourcePolymorphic, associationPolymorphic string
+				if polymorphic := field.TagSettings["POLYMORPHIC"]; polymorphic != "" {
+					sourcePolymorphic = ToDBName(polymorphic)
+					relationship.PolymorphicDBName = sourcePolymorphic + "_type"
+					if value, ok := field.TagSettings["POLYMORPHIC_VALUE"]; ok {
+						relationship.PolymorphicValue = polymorphicValue(value)
+					} else {
+						relationship.PolymorphicValue = func(ctx context.Context, singular bool) string {
+							return this.RealTableName(ctx, singular)
+						}
+					}
+				} else if polymorphic := field.TagSettings["ASSOCIATION_POLYMORPHIC"]; polymorphic != "" {
+					// Tag has many posts through taggings, then join table use taggable_id and
+					// taggable_type ('posts') as association foreign keys
+					associationPolymorphic = ToDBName(polymorphic)
+					relationship.PolymorphicDBName = associationPolymorphic + "_type"
+					if value, ok := field.TagSettings["POLYMORPHIC_VALUE"]; ok {
+						relationship.PolymorphicValue = polymorphicValue(value)
+					} else {
+						relationship.PolymorphicValue = func(ctx context.Context, singular bool) string {
+							return foreignStruct.RealTableName(ctx, singular)
+						}
+					}
+				}
+
 				{ // Foreign Keys for Source
 					joinTableDBNames := []string{}
 
@@ -159,6 +186,8 @@ func (this *ModelStruct) setup(pth []string, embedded bool, from *ModelStruct) (
 							if len(joinTableDBNames) > idx {
 								// if defined join table's foreign key
 								relationship.ForeignDBNames = append(relationship.ForeignDBNames, joinTableDBNames[idx])
+							} else if sourcePolymorphic != "" {
+								relationship.ForeignDBNames = append(relationship.ForeignDBNames, sourcePolymorphic+"_"+foreignField.DBName)
 							} else {
 								defaultJointableForeignKey := ToDBName(strings.TrimPrefix(reflectType.Name(), prefix)) + "_" + foreignField.DBName
 								relationship.ForeignDBNames = append(relationship.ForeignDBNames, defaultJointableForeignKey)
@@ -189,6 +218,8 @@ func (this *ModelStruct) setup(pth []string, embedded bool, from *ModelStruct) (
 							// setup join table foreign keys for association
 							if len(associationJoinTableDBNames) > idx {
 								relationship.AssociationForeignDBNames = append(relationship.AssociationForeignDBNames, associationJoinTableDBNames[idx])
+							} else if associationPolymorphic != "" {
+								relationship.AssociationForeignDBNames = append(relationship.AssociationForeignDBNames, associationPolymorphic+"_"+field.DBName)
 							} else {
 								// join table foreign keys for association
 								joinTableDBName := ToDBName(strings.TrimPrefix(elemType.Name(), prefix)) + "_" + field.DBName
@@ -711,6 +742,10 @@ func (this *ModelStruct) setup(pth []string, embedded bool, from *ModelStruct) (
 		if field.IsReadOnly {
 			this.DynamicFieldsByName[field.Name] = field
 		}
+		if expr := field.TagSettings["GENERATED"]; expr != "" && field.IsNormal {
+			field.Generated = &GeneratedColumn{Expr: expr, Stored: field.TagSettings.Flag("STORED")}
+			this.GeneratedFields = append(this.GeneratedFields, field)
+		}
 	}
 
 	if _, ok := this.FieldsByName[SoftDeleteFieldDeletedAt]; ok {
@@ -22,5 +22,10 @@ func (this *ModelStruct) setupIndexes() (err error) {
 		return errors.Wrap(err, "UNIQUE INDEX")
 	}
 
+	if tenantField := this.TenantField(); tenantField != nil {
+		this.UniqueIndexes.scopeToTenant(tenantField)
+	}
+
+	this.setupFullText()
 	return
 }
@@ -0,0 +1,324 @@
+package aorm
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// OutboxTable is the table of outbox messages
+const OutboxTable = "aorm_outbox"
+
+const (
+	DefaultOutboxBatchSize     = 100
+	DefaultOutboxPollInterval  = time.Second
+	DefaultOutboxLease         = 30 * time.Second
+	DefaultOutboxMaxAttempts   = 10
+	DefaultOutboxMinRetryDelay = time.Second
+	DefaultOutboxMaxRetryDelay = time.Hour
+)
+
+// ErrOutboxTransaction is returned by Publish out of transaction
+var ErrOutboxTransaction = errors.New("outbox: publish must be called into transaction")
+
+// OutboxEvent is the event with topic. The topic of other events is its type name.
+type OutboxEvent interface {
+	OutboxTopic() string
+}
+
+// OutboxKeyer is the event with key, e.g. the ID of aggregate, used by dispatcher to partition or order events
+type OutboxKeyer interface {
+	OutboxKey() string
+}
+
+// OutboxMessage is the event written to outbox
+type OutboxMessage struct {
+	ID          int64     `sql:"primary_key;auto_increment"`
+	Topic       string    `sql:"size:255;not null"`
+	Key         string    `sql:"size:255"`
+	Payload     string    `sql:"type:text;not null"`
+	CreatedAt   time.Time `sql:"not null"`
+	AvailableAt time.Time `sql:"not null;index"`
+	Attempts    int       `sql:"not null;default:0"`
+	LastError   string    `sql:"type:text"`
+	LeaseOwner  string    `sql:"size:255;index"`
+	LeaseUntil  *time.Time
+	DeliveredAt *time.Time `sql:"index"`
+	DeadAt      *time.Time
+}
+
+// TableName returns the OutboxTable
+func (OutboxMessage) TableName() string {
+	return OutboxTable
+}
+
+// Decode decodes the JSON payload into v
+func (this *OutboxMessage) Decode(v interface{}) error {
+	return errors.Wrapf(json.Unmarshal([]byte(this.Payload), v), "outbox: decode message %d", this.ID)
+}
+
+// NewOutboxMessage creates the message of event, encoded as JSON
+func NewOutboxMessage(event interface{}) (msg *OutboxMessage, err error) {
+	if msg, ok := event.(*OutboxMessage); ok {
+		return msg, nil
+	}
+	var payload []byte
+	if payload, err = json.Marshal(event); err != nil {
+		return nil, errors.Wrapf(err, "outbox: encode %T", event)
+	}
+	msg = &OutboxMessage{Payload: string(payload)}
+	if e, ok := event.(OutboxEvent); ok {
+		msg.Topic = e.OutboxTopic()
+	} else {
+		msg.Topic = indirectType(reflect.TypeOf(event)).Name()
+	}
+	if e, ok := event.(OutboxKeyer); ok {
+		msg.Key = e.OutboxKey()
+	}
+	return
+}
+
+// Publish writes the events to outbox into current transaction, so they are relayed, by OutboxRelay, only if
+// the transaction commits. The events are encoded as JSON, unless they are *OutboxMessage.
+//
+//	err := db.Transaction(func(tx *aorm.DB) error {
+//		if err := tx.Save(&order).Error; err != nil {
+//			return err
+//		}
+//		return tx.Publish(&OrderPlaced{OrderID: order.ID}).Error
+//	})
+func (s *DB) Publish(events ...interface{}) *DB {
+	var (
+		db         = s.New()
+		emptySQLTx *sql.Tx
+	)
+	if tx, ok := db.db.(sqlTx); !ok || tx == nil || tx == emptySQLTx {
+		db.AddError(ErrOutboxTransaction)
+		return db
+	}
+	now := NowFunc()
+	for _, event := range events {
+		msg, err := NewOutboxMessage(event)
+		if err != nil {
+			db.AddError(err)
+			return db
+		}
+		if msg.CreatedAt.IsZero() {
+			msg.CreatedAt = now
+		}
+		if msg.AvailableAt.IsZero() {
+			msg.AvailableAt = msg.CreatedAt
+		}
+		if err = db.Create(msg).Error; err != nil {
+			db.AddError(errors.Wrapf(err, "outbox: publish %q", msg.Topic))
+			return db
+		}
+	}
+	return db
+}
+
+// OutboxDispatcher delivers the message, e.g. to message broker. The messages are delivered at least once, so
+// the consumers must be idempotent.
+type OutboxDispatcher func(ctx context.Context, msg *OutboxMessage) error
+
+// OutboxRelay polls the outbox and hands the pending messages, oldest first, to dispatcher. The order is not
+// guaranteed across concurrent relays and retries, so consumers must not depend on it. The messages are
+// claimed by lease, selected using SKIP LOCKED where the dialect supports it, so many relays can run
+// concurrently and the messages of crashed relays are claimed again once its lease expires. The failed
+// messages are retried with backoff and dead-lettered after MaxAttempts.
+type OutboxRelay struct {
+	DB         *DB
+	Dispatcher OutboxDispatcher
+	// Owner identifies the relay into lease. Defaults to hostname and pid.
+	Owner string
+	// BatchSize is the maximum number of messages claimed by poll. Defaults to 100.
+	BatchSize int
+	// PollInterval is the delay between polls when outbox is empty. Defaults to 1s.
+	PollInterval time.Duration
+	// Lease is the time the relay holds the claimed messages. Defaults to 30s.
+	Lease time.Duration
+	// MaxAttempts is the number of deliveries before the message is dead-lettered. Defaults to 10.
+	MaxAttempts int
+	// MinRetryDelay is the delay before the first retry, doubled on each retry. Defaults to 1s.
+	MinRetryDelay time.Duration
+	// MaxRetryDelay limits the delay between retries. Defaults to 1h.
+	MaxRetryDelay time.Duration
+	// OnError is called with the errors of polls and deliveries
+	OnError func(msg *OutboxMessage, err error)
+
+	claims uint64
+}
+
+// NewOutboxRelay creates the relay of db outbox
+func NewOutboxRelay(db *DB, dispatcher OutboxDispatcher) *OutboxRelay {
+	return &OutboxRelay{DB: db, Dispatcher: dispatcher}
+}
+
+func (this *OutboxRelay) defaults() {
+	if this.Owner == "" {
+		host, _ := os.Hostname()
+		this.Owner = fmt.Sprintf("%s:%d", host, os.Getpid())
+	}
+	if this.BatchSize <= 0 {
+		this.BatchSize = DefaultOutboxBatchSize
+	}
+	if this.PollInterval <= 0 {
+		this.PollInterval = DefaultOutboxPollInterval
+	}
+	if this.Lease <= 0 {
+		this.Lease = DefaultOutboxLease
+	}
+	if this.MaxAttempts <= 0 {
+		this.MaxAttempts = DefaultOutboxMaxAttempts
+	}
+	if this.MinRetryDelay <= 0 {
+		this.MinRetryDelay = DefaultOutboxMinRetryDelay
+	}
+	if this.MaxRetryDelay <= 0 {
+		this.MaxRetryDelay = DefaultOutboxMaxRetryDelay
+	}
+}
+
+// Run relays the messages until ctx is done
+func (this *OutboxRelay) Run(ctx context.Context) error {
+	for {
+		n, err := this.RelayOnce(ctx)
+		if err != nil && this.OnError != nil {
+			this.OnError(nil, err)
+		}
+		if n > 0 && err == nil {
+			// the outbox may have more messages
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			continue
+		}
+		timer := time.NewTimer(this.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// RelayOnce claims a batch of pending messages and dispatches them, returning the number of claimed messages
+func (this *OutboxRelay) RelayOnce(ctx context.Context) (n int, err error) {
+	this.defaults()
+	var msgs []*OutboxMessage
+	if msgs, err = this.claim(); err != nil || len(msgs) == 0 {
+		return
+	}
+	for _, msg := range msgs {
+		if err = ctx.Err(); err != nil {
+			// the remaining messages are claimed again when the lease expires
+			return len(msgs), err
+		}
+		if err := this.deliver(ctx, msg); err != nil && this.OnError != nil {
+			this.OnError(msg, err)
+		}
+	}
+	return len(msgs), nil
+}
+
+func (this *OutboxRelay) pending(db *DB, now time.Time) *DB {
+	return db.Opt(OptForceSingleUpdate()).Model(&OutboxMessage{}).
+		Where("delivered_at IS NULL AND dead_at IS NULL AND available_at <= ?", now).
+		Where("lease_until IS NULL OR lease_until < ?", now)
+}
+
+// claim leases the pending messages to relay. The lease owner is unique by claim, so the claimed messages are
+// selected by it.
+func (this *OutboxRelay) claim() (msgs []*OutboxMessage, err error) {
+	var (
+		now   = NowFunc()
+		until = now.Add(this.Lease)
+		owner = this.Owner + "#" + strconv.FormatUint(atomic.AddUint64(&this.claims, 1), 10)
+	)
+	err = this.DB.Transaction(func(tx *DB) (err error) {
+		var (
+			pending []OutboxMessage
+			ids     []int64
+			q       = this.pending(tx, now).Select("id").Order("id").Limit(this.BatchSize)
+		)
+		if lock := tx.Dialect().SkipLockedSQL(); lock != "" {
+			q = q.Set("aorm:query_option", lock)
+		}
+		if err = q.Find(&pending).Error; err != nil || len(pending) == 0 {
+			return
+		}
+		for _, msg := range pending {
+			ids = append(ids, msg.ID)
+		}
+		// the pending condition guards the dialects without row locks against concurrent claims
+		return this.pending(tx, now).
+			Where("id IN (?)", ids).
+			UpdateColumns(map[string]interface{}{"lease_owner": owner, "lease_until": until}).Error
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "outbox: claim")
+	}
+	if err = this.DB.Where("lease_owner = ?", owner).Order("id").Find(&msgs).Error; err != nil {
+		return nil, errors.Wrap(err, "outbox: claim")
+	}
+	return
+}
+
+func (this *OutboxRelay) deliver(ctx context.Context, msg *OutboxMessage) (err error) {
+	var (
+		dispatchErr = this.dispatch(ctx, msg)
+		now         = NowFunc()
+		values      = map[string]interface{}{"lease_owner": "", "lease_until": nil}
+	)
+	if dispatchErr == nil {
+		values["delivered_at"] = now
+	} else {
+		msg.Attempts++
+		values["attempts"] = msg.Attempts
+		values["last_error"] = dispatchErr.Error()
+		if msg.Attempts >= this.MaxAttempts {
+			values["dead_at"] = now
+		} else {
+			values["available_at"] = now.Add(this.retryDelay(msg.Attempts))
+		}
+	}
+	// the lease owner guards against update of message claimed again by other relay after lease expired
+	if err = this.DB.Opt(OptForceSingleUpdate()).Model(&OutboxMessage{}).
+		Where("id = ? AND lease_owner = ?", msg.ID, msg.LeaseOwner).
+		UpdateColumns(values).Error; err != nil {
+		return errors.Wrapf(err, "outbox: mark message %d", msg.ID)
+	}
+	if dispatchErr != nil {
+		return errors.Wrapf(dispatchErr, "outbox: dispatch message %d of %q", msg.ID, msg.Topic)
+	}
+	return
+}
+
+func (this *OutboxRelay) dispatch(ctx context.Context, msg *OutboxMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return this.Dispatcher(ctx, msg)
+}
+
+func (this *OutboxRelay) retryDelay(attempts int) time.Duration {
+	d := this.MinRetryDelay
+	for i := 1; i < attempts && d < this.MaxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > this.MaxRetryDelay {
+		d = this.MaxRetryDelay
+	}
+	return d
+}
@@ -0,0 +1,75 @@
+package aorm_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type OrderPlaced struct {
+	OrderID int
+}
+
+func (OrderPlaced) OutboxTopic() string {
+	return "order.placed"
+}
+
+func TestOutbox(t *testing.T) {
+	DB.DropTableIfExists(&aorm.OutboxMessage{})
+	DB.AutoMigrate(&aorm.OutboxMessage{})
+
+	if err := DB.Publish(&OrderPlaced{1}).Error; err != aorm.ErrOutboxTransaction {
+		t.Errorf("publish out of transaction should fail, but got %v", err)
+	}
+
+	DB.Transaction(func(tx *aorm.DB) error {
+		tx.Publish(&OrderPlaced{1})
+		return errors.New("abort")
+	})
+	if err := DB.Transaction(func(tx *aorm.DB) error {
+		return tx.Publish(&OrderPlaced{2}, &OrderPlaced{3}).Error
+	}); err != nil {
+		t.Fatalf("no error should happen when publish, but got %v", err)
+	}
+
+	var delivered []int
+	relay := aorm.NewOutboxRelay(DB, func(ctx context.Context, msg *aorm.OutboxMessage) error {
+		var e OrderPlaced
+		if err := msg.Decode(&e); err != nil {
+			return err
+		}
+		if msg.Topic != "order.placed" {
+			t.Errorf("topic should be order.placed, but got %v", msg.Topic)
+		}
+		delivered = append(delivered, e.OrderID)
+		return nil
+	})
+	if n, err := relay.RelayOnce(context.Background()); err != nil || n != 2 {
+		t.Fatalf("should relay 2 messages, but got %v, %v", n, err)
+	}
+	if len(delivered) != 2 || delivered[0] != 2 || delivered[1] != 3 {
+		t.Errorf("should deliver the committed messages oldest first, but got %v", delivered)
+	}
+	if n, _ := relay.RelayOnce(context.Background()); n != 0 {
+		t.Errorf("delivered messages should not be relayed again, but got %v", n)
+	}
+
+	DB.Transaction(func(tx *aorm.DB) error {
+		return tx.Publish(&OrderPlaced{4}).Error
+	})
+	failing := aorm.NewOutboxRelay(DB, func(ctx context.Context, msg *aorm.OutboxMessage) error {
+		return errors.New("broker down")
+	})
+	failing.MaxAttempts = 1
+	failing.RelayOnce(context.Background())
+
+	var dead aorm.OutboxMessage
+	if err := DB.Where("dead_at IS NOT NULL").First(&dead).Error; err != nil {
+		t.Fatalf("failed message should be dead-lettered, but got %v", err)
+	}
+	if dead.Attempts != 1 || dead.LastError != "broker down" {
+		t.Errorf("dead message should have attempts and last error, but got %+v", dead)
+	}
+}
@@ -0,0 +1,237 @@
+package aorm
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Cursor is the keyset pagination request.
+type Cursor struct {
+	// After is the token of the record after which the page starts
+	After string
+	// Before is the token of the record before which the page ends
+	Before string
+	// Size is the page size
+	Size int
+	// Order is the list of field names prefixed with `-` for descending order. The primary fields are
+	// appended, if not present, to make the order unique. Order fields must not be NULL.
+	Order []string
+}
+
+// Page is the keyset pagination result.
+type Page struct {
+	// Next is the token of next page, or empty if is the last page
+	Next string
+	// Prev is the token of previous page, or empty if is the first page
+	Prev string
+}
+
+type cursorOrder struct {
+	field *StructField
+	desc  bool
+}
+
+// Paginate find a page of records into out, a pointer to slice, using keyset (seek) predicates over the
+// cursor order fields and primary key, instead of OFFSET. Returns the opaque tokens of next and previous pages.
+//
+//	page, err := db.Where("active").Paginate(&users, aorm.Cursor{After: token, Size: 50, Order: []string{"-CreatedAt"}})
+func (s *DB) Paginate(out interface{}, cursor Cursor) (page *Page, err error) {
+	if cursor.After != "" && cursor.Before != "" {
+		return nil, errors.New("paginate: cursor After and Before are mutually exclusive")
+	}
+	if cursor.Size <= 0 {
+		return nil, errors.New("paginate: cursor size must be greater than zero")
+	}
+
+	var (
+		scope     = s.NewScope(out)
+		model     = scope.Struct()
+		tableName = scope.QuotedTableName()
+		orders    []cursorOrder
+		backward  = cursor.Before != ""
+		token     = cursor.After
+	)
+
+	if backward {
+		token = cursor.Before
+	}
+
+	if len(model.PrimaryFields) == 0 {
+		return nil, errors.Errorf("paginate: %s does not have primary fields", model.Type)
+	}
+
+	for _, name := range cursor.Order {
+		var o cursorOrder
+		if strings.HasPrefix(name, "-") {
+			o.desc, name = true, name[1:]
+		}
+		if o.field, _ = model.FieldByName(name); o.field == nil || !o.field.IsNormal {
+			return nil, errors.Errorf("paginate: invalid order field %q of %s", name, model.Type)
+		}
+		orders = append(orders, o)
+	}
+
+PrimaryFields:
+	for _, f := range model.PrimaryFields {
+		for _, o := range orders {
+			if o.field == f {
+				continue PrimaryFields
+			}
+		}
+		orders = append(orders, cursorOrder{field: f})
+	}
+
+	db := s
+	if token != "" {
+		var values []interface{}
+		if values, err = decodeCursor(model, orders, token); err != nil {
+			return nil, err
+		}
+
+		// (c1 > v1) OR (c1 = v1 AND c2 > v2) OR ...
+		var (
+			or   []string
+			args []interface{}
+		)
+		for i, o := range orders {
+			var and []string
+			for j := 0; j < i; j++ {
+				and = append(and, fmt.Sprintf("%v.%v = ?", tableName, scope.Quote(orders[j].field.DBName)))
+				args = append(args, values[j])
+			}
+			op := ">"
+			if o.desc != backward {
+				op = "<"
+			}
+			and = append(and, fmt.Sprintf("%v.%v %v ?", tableName, scope.Quote(o.field.DBName), op))
+			args = append(args, values[i])
+			or = append(or, "("+strings.Join(and, " AND ")+")")
+		}
+		db = db.Where("("+strings.Join(or, " OR ")+")", args...)
+	}
+
+	for i, o := range orders {
+		order := fmt.Sprintf("%v.%v", tableName, scope.Quote(o.field.DBName))
+		if o.desc != backward {
+			order += " DESC"
+		}
+		db = db.Order(order, i == 0)
+	}
+
+	if err = db.Limit(cursor.Size + 1).Find(out).Error; err != nil {
+		return
+	}
+
+	var (
+		results = indirect(reflect.ValueOf(out))
+		hasMore = results.Len() > cursor.Size
+	)
+
+	if hasMore {
+		results.Set(results.Slice(0, cursor.Size))
+	}
+
+	if backward {
+		swap := reflect.Swapper(results.Interface())
+		for i, j := 0, results.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+
+	page = &Page{}
+	if results.Len() == 0 {
+		return
+	}
+
+	first, last := results.Index(0), results.Index(results.Len()-1)
+	if backward {
+		page.Next = encodeCursor(model, orders, last)
+		if hasMore {
+			page.Prev = encodeCursor(model, orders, first)
+		}
+	} else {
+		if hasMore {
+			page.Next = encodeCursor(model, orders, last)
+		}
+		if token != "" {
+			page.Prev = encodeCursor(model, orders, first)
+		}
+	}
+	return
+}
+
+// encodeCursor encodes the non primary order values as length prefixed JSON, followed by the `Id.Bytes()` of
+// record, into base64 token.
+func encodeCursor(model *ModelStruct, orders []cursorOrder, record reflect.Value) string {
+	var (
+		b  []byte
+		rv = indirect(record)
+	)
+	for _, o := range orders {
+		if o.field.IsPrimaryKey {
+			continue
+		}
+		var size [binary.MaxVarintLen64]byte
+		data, _ := json.Marshal(rv.FieldByIndex(o.field.StructIndex).Interface())
+		b = append(b, size[:binary.PutUvarint(size[:], uint64(len(data)))]...)
+		b = append(b, data...)
+	}
+	b = append(b, model.GetID(rv).Bytes()...)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor decodes token into order values, the primary fields values are the raw values of id
+func decodeCursor(model *ModelStruct, orders []cursorOrder, token string) (values []interface{}, err error) {
+	var b []byte
+	if b, err = base64.RawURLEncoding.DecodeString(token); err != nil {
+		return nil, errors.Wrap(err, "paginate: decode cursor")
+	}
+
+	var pkValues = map[*StructField]interface{}{}
+	values = make([]interface{}, len(orders))
+
+	for i, o := range orders {
+		if o.field.IsPrimaryKey {
+			continue
+		}
+		size, n := binary.Uvarint(b)
+		if n <= 0 || uint64(len(b)-n) < size {
+			return nil, errors.New("paginate: invalid cursor")
+		}
+		value := reflect.New(o.field.Struct.Type)
+		if err = json.Unmarshal(b[n:n+int(size)], value.Interface()); err != nil {
+			return nil, errors.Wrapf(err, "paginate: decode cursor value of %q", o.field.Name)
+		}
+		values[i] = value.Elem().Interface()
+		b = b[n+int(size):]
+	}
+
+	// same format of Id.Bytes()
+	for _, f := range model.PrimaryFields {
+		if len(b) == 0 || len(b) < int(b[0])+1 {
+			return nil, errors.New("paginate: invalid cursor id")
+		}
+		var valuer IDValuer
+		if valuer, err = f.DefaultID(); err != nil {
+			return
+		}
+		if valuer, err = valuer.ParseBytes(b[1 : int(b[0])+1]); err != nil {
+			return nil, errors.Wrap(err, "paginate: decode cursor id")
+		}
+		pkValues[f] = valuer.Raw()
+		b = b[int(b[0])+1:]
+	}
+
+	for i, o := range orders {
+		if o.field.IsPrimaryKey {
+			values[i] = pkValues[o.field]
+		}
+	}
+	return
+}
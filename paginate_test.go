@@ -0,0 +1,59 @@
+package aorm_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type PagedItem struct {
+	ID    int
+	Score int
+}
+
+func TestPaginate(t *testing.T) {
+	DB.DropTableIfExists(&PagedItem{})
+	DB.AutoMigrate(&PagedItem{})
+	for i, score := range []int{5, 3, 5, 1, 4, 2, 5} {
+		DB.Save(&PagedItem{ID: i + 1, Score: score})
+	}
+
+	ids := func(items []PagedItem) (r []int) {
+		for _, item := range items {
+			r = append(r, item.ID)
+		}
+		return
+	}
+
+	var (
+		items  []PagedItem
+		cursor = aorm.Cursor{Size: 3, Order: []string{"-Score"}}
+		pages  [][]int
+	)
+	for {
+		page, err := DB.Paginate(&items, cursor)
+		if err != nil {
+			t.Fatalf("no error should happen when paginate, but got %v", err)
+		}
+		pages = append(pages, ids(items))
+		if page.Next == "" {
+			break
+		}
+		cursor.After = page.Next
+	}
+
+	if expected := [][]int{{1, 3, 7}, {5, 2, 6}, {4}}; !reflect.DeepEqual(pages, expected) {
+		t.Errorf("pages should be %v, but got %v", expected, pages)
+	}
+
+	// back from last page
+	page, _ := DB.Paginate(&items, cursor)
+	page, _ = DB.Paginate(&items, aorm.Cursor{Before: page.Prev, Size: 3, Order: []string{"-Score"}})
+	if expected := []int{5, 2, 6}; !reflect.DeepEqual(ids(items), expected) {
+		t.Errorf("previous page should be %v, but got %v", expected, ids(items))
+	}
+	if page.Prev == "" || page.Next == "" {
+		t.Errorf("previous page should have prev and next tokens")
+	}
+}
@@ -0,0 +1,70 @@
+package aorm_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/moisespsena-go/aorm/dialects/postgres"
+)
+
+type PartitionedEvent struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+}
+
+func (PartitionedEvent) TableName() string {
+	return "partitioned_events"
+}
+
+func TestPartitionLifecycle(t *testing.T) {
+	if dialect := os.Getenv("AORM_DIALECT"); dialect != "postgres" {
+		t.Skip("Skipping this because only postgres supports partitions")
+	}
+
+	if err := postgres.Partition(DB.StructOf(&PartitionedEvent{}), postgres.DatePartition{
+		Field:     "CreatedAt",
+		Type:      postgres.DatePartitionMonthly,
+		Premake:   1,
+		Retention: 1,
+	}); err != nil {
+		t.Fatalf("no error should happen when declare partition, but got %v", err)
+	}
+
+	DB.DropTableIfExists(&PartitionedEvent{})
+	if err := DB.CreateTable(&PartitionedEvent{}).Error; err != nil {
+		t.Fatalf("no error should happen when create partitioned table, but got %v", err)
+	}
+
+	var (
+		now     = time.Now().UTC()
+		current = postgres.DatePartitionMonthly.SpecOf(now)
+		next    = postgres.DatePartitionMonthly.SpecOf(current.To)
+		old     = now.AddDate(0, -6, 0)
+	)
+
+	if err := postgres.MaintainPartitions(DB, now, &PartitionedEvent{}); err != nil {
+		t.Fatalf("no error should happen when maintain partitions, but got %v", err)
+	}
+	for _, spec := range []postgres.TableDatePartitionSpec{current, next} {
+		if !DB.Dialect().HasTable(spec.TableName("partitioned_events")) {
+			t.Errorf("partition %v should be created by maintenance", spec.Name)
+		}
+	}
+
+	if err := DB.Save(&PartitionedEvent{Name: "old", CreatedAt: old}).Error; err != nil {
+		t.Fatalf("out of range insert should create the partition on demand, but got %v", err)
+	}
+	oldPartition := postgres.DatePartitionMonthly.SpecOf(old).TableName("partitioned_events")
+	if !DB.Dialect().HasTable(oldPartition) {
+		t.Errorf("partition %v should be created on demand", oldPartition)
+	}
+
+	if err := postgres.MaintainPartitions(DB, now, &PartitionedEvent{}); err != nil {
+		t.Fatalf("no error should happen when maintain partitions, but got %v", err)
+	}
+	if DB.Dialect().HasTable(oldPartition) {
+		t.Errorf("partition %v should be dropped after retention", oldPartition)
+	}
+}
@@ -0,0 +1,109 @@
+package aorm_test
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type Label struct {
+	Id   int
+	Name string
+}
+
+type Article struct {
+	Id     int
+	Title  string
+	Labels []Label `aorm:"m2m:labelings;polymorphic:Labelable"`
+}
+
+type Video struct {
+	Id     int
+	Title  string
+	Labels []Label `aorm:"m2m:labelings;polymorphic:Labelable"`
+}
+
+func TestPolymorphicMany2Many(t *testing.T) {
+	DB.DropTableIfExists("labelings", &Label{}, &Article{}, &Video{})
+	DB.AutoMigrate(&Label{}, &Article{}, &Video{})
+
+	if !DB.Dialect().HasColumn("labelings", "labelable_type") {
+		t.Fatalf("join table should have polymorphic type column")
+	}
+
+	article := Article{Id: 1, Title: "article", Labels: []Label{{Name: "go"}, {Name: "orm"}}}
+	video := Video{Id: 1, Title: "video", Labels: []Label{{Name: "talk"}}}
+	DB.Save(&article).Save(&video)
+
+	if count := DB.Model(&article).Association("Labels").Count(); count != 2 {
+		t.Errorf("Article's labels count should be 2, but got %v", count)
+	}
+
+	if count := DB.Model(&video).Association("Labels").Count(); count != 1 {
+		t.Errorf("Video's labels count should be 1, but got %v", count)
+	}
+
+	var articles []Article
+	DB.Preload("Labels").Find(&articles)
+	if len(articles) != 1 || !compareLabels(articles[0].Labels, []string{"go", "orm"}) {
+		t.Errorf("Article's labels should be preloaded, but got %#v", articles)
+	}
+
+	var videos []Video
+	DB.InlinePreload("Labels").Find(&videos)
+	if len(videos) != 1 || !compareLabels(videos[0].Labels, []string{"talk"}) {
+		t.Errorf("Video's labels should be preloaded, but got %#v", videos)
+	}
+
+	DB.Model(&video).Association("Labels").Clear()
+	if count := DB.Model(&article).Association("Labels").Count(); count != 2 {
+		t.Errorf("Clear video's labels should not clear article's labels, but got %v", count)
+	}
+}
+
+type ArticleComment struct {
+	Id        int
+	ArticleID int
+	Article   Article
+}
+
+func TestInlinePreloadMany2Many(t *testing.T) {
+	DB.DropTableIfExists("labelings", &Label{}, &Article{}, &ArticleComment{})
+	DB.AutoMigrate(&Label{}, &Article{}, &ArticleComment{})
+
+	article := Article{Id: 1, Title: "article", Labels: []Label{{Name: "go"}, {Name: "orm"}}}
+	DB.Save(&article)
+	DB.Save(&ArticleComment{Id: 1, ArticleID: article.Id})
+	DB.Save(&ArticleComment{Id: 2, ArticleID: article.Id})
+
+	var articles []Article
+	if err := DB.InlinePreload("Labels").Find(&articles).Error; err != nil {
+		t.Fatalf("no error should happen when inline preload many2many, but got %v", err)
+	}
+	if len(articles) != 1 || !compareLabels(articles[0].Labels, []string{"go", "orm"}) {
+		t.Errorf("many2many should be preloaded after query, but got %#v", articles)
+	}
+
+	var comments []ArticleComment
+	if err := DB.InlinePreload("Article.Labels").Find(&comments).Error; err != nil {
+		t.Fatalf("no error should happen when inline preload many2many in nested path, but got %v", err)
+	}
+	if len(comments) != 2 {
+		t.Fatalf("should find 2 comments, but got %v", len(comments))
+	}
+	for _, comment := range comments {
+		if comment.Article.Title != "article" || !compareLabels(comment.Article.Labels, []string{"go", "orm"}) {
+			t.Errorf("article and its labels should be preloaded, but got %#v", comment.Article)
+		}
+	}
+}
+
+func compareLabels(labels []Label, contents []string) bool {
+	var names []string
+	for _, label := range labels {
+		names = append(names, label.Name)
+	}
+	sort.Strings(names)
+	sort.Strings(contents)
+	return reflect.DeepEqual(names, contents)
+}
@@ -0,0 +1,197 @@
+package reverse
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+// Change is the difference between models and database
+type Change struct {
+	// Op is `+` if exists in models but not in database, or `-` if exists in database but not in models
+	Op byte
+	// Kind is one of `table`, `column`, `index` or `foreign key`
+	Kind  string
+	Table string
+	Name  string
+}
+
+func (this Change) String() string {
+	s := string(this.Op) + " " + this.Kind + " " + this.Table
+	if this.Name != "" {
+		s += "." + this.Name
+	}
+	return s
+}
+
+// Diff compares the tables of models, with its children and join tables, against database. Tables of database
+// without models are ignored.
+func Diff(db *aorm.DB, schema string, models ...interface{}) (changes []Change, err error) {
+	var (
+		modelStructs []*aorm.ModelStruct
+		seen         = map[*aorm.ModelStruct]bool{}
+		add          func(ms *aorm.ModelStruct)
+	)
+	add = func(ms *aorm.ModelStruct) {
+		if seen[ms] {
+			return
+		}
+		seen[ms] = true
+		modelStructs = append(modelStructs, ms)
+		for _, child := range ms.Children {
+			add(child)
+		}
+		for _, child := range ms.HasManyChildren {
+			add(child)
+		}
+	}
+	for _, model := range models {
+		ms, err := db.ModelStructStorage().GetOrNew(model)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reverse: diff of %T", model)
+		}
+		add(ms)
+	}
+
+	actual, err := Inspect(db, schema)
+	if err != nil {
+		return
+	}
+	var (
+		tables     = map[string]*Table{}
+		joinTables = map[string]bool{}
+	)
+	for _, table := range actual {
+		tables[table.Name] = table
+	}
+
+	// the foreign keys are declared on any side of relationship
+	var foreignKeys = map[string]map[string]string{}
+	for _, ms := range modelStructs {
+		scope := db.NewModelScope(ms, ms.Value)
+		for _, fk := range ms.ForeignKeys {
+			def := fk.Definition(scope)
+			_, src := aorm.SplitSchemaTableName(def.SrcTableName)
+			_, dst := aorm.SplitSchemaTableName(def.DstTableName)
+			if foreignKeys[src] == nil {
+				foreignKeys[src] = map[string]string{}
+			}
+			foreignKeys[src][foreignKeyKey(def.SrcColumns, dst)] = def.Name
+		}
+	}
+
+	for _, ms := range modelStructs {
+		scope := db.NewModelScope(ms, ms.Value)
+		_, tableName := aorm.SplitSchemaTableName(scope.TableName())
+
+		for _, field := range ms.Fields {
+			if rel := field.Relationship; rel != nil && rel.JoinTableHandler != nil {
+				_, name := aorm.SplitSchemaTableName(rel.JoinTableHandler.Table(db))
+				if !joinTables[name] {
+					joinTables[name] = true
+					if tables[name] == nil {
+						changes = append(changes, Change{Op: '+', Kind: "table", Table: name})
+					}
+				}
+			}
+		}
+
+		table := tables[tableName]
+		if table == nil {
+			changes = append(changes, Change{Op: '+', Kind: "table", Table: tableName})
+			continue
+		}
+		changes = append(changes, diffTable(db, ms, table, foreignKeys[tableName])...)
+	}
+	return
+}
+
+func diffTable(db *aorm.DB, ms *aorm.ModelStruct, table *Table, foreignKeys map[string]string) (changes []Change) {
+	var columns = map[string]bool{}
+	for _, field := range ms.Fields {
+		if field.IsNormal {
+			columns[field.DBName] = true
+		}
+	}
+	if ms.FullText != nil && db.Dialect().GetName() == "postgres" {
+		columns[aorm.FullTextColumn] = true
+	}
+	for _, field := range ms.Fields {
+		if field.IsNormal && table.Column(field.DBName) == nil {
+			changes = append(changes, Change{Op: '+', Kind: "column", Table: table.Name, Name: field.DBName})
+		}
+	}
+	for _, column := range table.Columns {
+		if !columns[column.Name] {
+			changes = append(changes, Change{Op: '-', Kind: "column", Table: table.Name, Name: column.Name})
+		}
+	}
+
+	var indexes = map[string]bool{}
+	for _, ixs := range []aorm.IndexMap{ms.Indexes, ms.UniqueIndexes} {
+		var names []string
+		for _, ix := range ixs {
+			names = append(names, ix.BuildName(db.Dialect(), table.Name))
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			indexes[name] = true
+			if !hasIndex(table, name) {
+				changes = append(changes, Change{Op: '+', Kind: "index", Table: table.Name, Name: name})
+			}
+		}
+	}
+	for _, ix := range table.Indexes {
+		if !indexes[ix.Name] && !strings.HasPrefix(ix.Name, "sqlite_autoindex_") && !isFullTextIndex(ms, ix.Name) {
+			changes = append(changes, Change{Op: '-', Kind: "index", Table: table.Name, Name: ix.Name})
+		}
+	}
+
+	// foreign keys are compared by columns, because sqlite does not keep the constraint names
+	var keys []string
+	for key := range foreignKeys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !hasForeignKey(table, key) {
+			changes = append(changes, Change{Op: '+', Kind: "foreign key", Table: table.Name, Name: foreignKeys[key]})
+		}
+	}
+	for _, fk := range table.ForeignKeys {
+		if _, ok := foreignKeys[foreignKeyKey(fk.Columns, fk.RefTable)]; !ok {
+			changes = append(changes, Change{Op: '-', Kind: "foreign key", Table: table.Name, Name: fk.Name})
+		}
+	}
+	return
+}
+
+func hasIndex(table *Table, name string) bool {
+	for _, ix := range table.Indexes {
+		if ix.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isFullTextIndex(ms *aorm.ModelStruct, name string) bool {
+	return ms.FullText != nil && (strings.HasPrefix(name, "ix_") && strings.HasSuffix(name, "_"+aorm.FullTextColumn) ||
+		strings.HasPrefix(name, "ft_"))
+}
+
+func hasForeignKey(table *Table, key string) bool {
+	for _, fk := range table.ForeignKeys {
+		if foreignKeyKey(fk.Columns, fk.RefTable) == key {
+			return true
+		}
+	}
+	return false
+}
+
+func foreignKeyKey(columns []string, refTable string) string {
+	return strings.Join(columns, ",") + ">" + refTable
+}
@@ -0,0 +1,78 @@
+package reverse_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+	_ "github.com/moisespsena-go/aorm/dialects/sqlite"
+	"github.com/moisespsena-go/aorm/reverse"
+)
+
+type DiffItemV1 struct {
+	ID     int    `sql:"primary_key;auto_increment"`
+	Name   string `sql:"index"`
+	Legacy string
+}
+
+func (DiffItemV1) TableName() string {
+	return "diff_items"
+}
+
+type DiffItem struct {
+	ID   int `sql:"primary_key;auto_increment"`
+	Name string
+	Code string `sql:"index"`
+}
+
+func (DiffItem) TableName() string {
+	return "diff_items"
+}
+
+type DiffTag struct {
+	ID   int `sql:"primary_key;auto_increment"`
+	Name string
+}
+
+func TestDiff(t *testing.T) {
+	db, err := aorm.Open("sqlite3", filepath.Join(os.TempDir(), "aorm_reverse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.DropTableIfExists(&DiffItemV1{}, &DiffTag{})
+
+	migrator := db.Migrator()
+	if err = migrator.AutoMigrate(&DiffItemV1{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = migrator.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := reverse.Diff(db, "", &DiffItemV1{})
+	if err != nil || len(changes) != 0 {
+		t.Errorf("migrated model should not have changes, but got %v, %v", changes, err)
+	}
+
+	changes, err = reverse.Diff(db, "", &DiffItem{}, &DiffTag{})
+	if err != nil {
+		t.Fatalf("no error should happen when diff, but got %v", err)
+	}
+	var result []string
+	for _, change := range changes {
+		result = append(result, change.String())
+	}
+	expected := []string{
+		"+ column diff_items.code",
+		"- column diff_items.legacy",
+		"+ index diff_items.ix_diff_items_code",
+		"- index diff_items.ix_diff_items_name",
+		"+ table " + db.NewScope(&DiffTag{}).TableName(),
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("changes should be %v, but got %v", expected, result)
+	}
+}
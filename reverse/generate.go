@@ -0,0 +1,369 @@
+package reverse
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jinzhu/inflection"
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+const (
+	importAorm  = "github.com/moisespsena-go/aorm"
+	importBid   = "github.com/moisespsena-go/bid"
+	importTypes = "github.com/moisespsena-go/aorm/types"
+	importTime  = "time"
+)
+
+var initialisms = map[string]string{
+	"id":   "ID",
+	"ip":   "IP",
+	"url":  "URL",
+	"uri":  "URI",
+	"uuid": "UUID",
+	"api":  "API",
+	"json": "JSON",
+	"html": "HTML",
+	"http": "HTTP",
+	"sql":  "SQL",
+}
+
+// GoName returns the exported go identifier of database name, e.g. `author_id` -> `AuthorID`
+func GoName(name string) string {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return r == '_' || r == '-' || r == ' ' || r == '.'
+	})
+	for i, part := range parts {
+		if v, ok := initialisms[strings.ToLower(part)]; ok {
+			parts[i] = v
+		} else {
+			parts[i] = aorm.NamifyString(part)
+		}
+	}
+	name = strings.Join(parts, "")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "X" + name
+	}
+	return name
+}
+
+// StructName returns the struct name of table, e.g. `order_items` -> `OrderItem`
+func StructName(table string) string {
+	return GoName(inflection.Singular(table))
+}
+
+// GoType returns the go type of column and the import path it requires
+func GoType(column *Column) (typ, pkg string) {
+	switch t := column.Type; t {
+	case "bool", "boolean":
+		typ = "bool"
+	case "int2", "smallint", "smallserial":
+		typ = "int16"
+	case "int4", "int", "integer", "mediumint", "serial":
+		typ = "int"
+	case "int8", "bigint", "bigserial":
+		typ = "int64"
+	case "float4", "real":
+		typ = "float32"
+	case "float8", "float", "double", "double precision", "numeric", "decimal":
+		typ = "float64"
+	case "bytea", "blob":
+		typ = "[]byte"
+	case "date", "datetime", "time", "timetz", "timestamp", "timestamptz",
+		"timestamp with time zone", "timestamp without time zone":
+		typ, pkg = "time.Time", importTime
+	case "json", "jsonb":
+		typ, pkg = "types.JSON[map[string]interface{}]", importTypes
+	default:
+		// sqlite type affinity rules
+		switch {
+		case strings.Contains(t, "int"):
+			typ = "int64"
+		case strings.Contains(t, "char"), strings.Contains(t, "clob"), strings.Contains(t, "text"):
+			typ = "string"
+		case t == "":
+			typ = "[]byte"
+		case strings.Contains(t, "real"), strings.Contains(t, "floa"), strings.Contains(t, "doub"):
+			typ = "float64"
+		default:
+			typ = "string"
+		}
+	}
+	if column.Nullable && typ != "[]byte" && pkg != importTypes {
+		typ = "*" + typ
+	}
+	return
+}
+
+// Generate writes the go source of package pkg with the models of tables.
+//
+// The embeddable aorm.Model, aorm.Timestamps and aorm.SoftDelete are used when the columns of table matches
+// them. The single column foreign keys generates the belongs to fields and, if unambiguous, the has many
+// fields on referenced model. The foreign keys to the primary key of aorm.Model are bid.BID.
+func Generate(w io.Writer, pkg string, tables []*Table) (err error) {
+	g := &generator{
+		tables:  map[string]*Table{},
+		imports: map[string]bool{},
+	}
+	for _, table := range tables {
+		g.tables[table.Name] = table
+	}
+
+	var body bytes.Buffer
+	for _, table := range tables {
+		g.model(&body, table)
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by aorm-reverse. DO NOT EDIT.\n\n")
+	src.WriteString("package " + pkg + "\n\n")
+	if len(g.imports) > 0 {
+		var imports []string
+		for imp := range g.imports {
+			imports = append(imports, imp)
+		}
+		sort.Strings(imports)
+		src.WriteString("import (\n")
+		for _, imp := range imports {
+			src.WriteString(strconv.Quote(imp) + "\n")
+		}
+		src.WriteString(")\n\n")
+	}
+	src.Write(body.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return errors.Wrap(err, "reverse: format source")
+	}
+	_, err = w.Write(formatted)
+	return
+}
+
+type generator struct {
+	tables  map[string]*Table
+	imports map[string]bool
+}
+
+type generatorField struct {
+	name, typ string
+	tags      []string
+}
+
+func (this *generator) model(w io.Writer, table *Table) {
+	var (
+		name     = StructName(table.Name)
+		embedded = map[string]bool{}
+		fields   []*generatorField
+		used     = map[string]bool{}
+		byColumn = map[string]*generatorField{}
+	)
+
+	if this.isModel(table) {
+		embedded["id"] = true
+		fields = append(fields, &generatorField{typ: "aorm.Model"})
+		this.imports[importAorm] = true
+	}
+	if created, updated := table.Column(aorm.TimestampColumnCreatedAt), table.Column(aorm.TimestampColumnUpdatedAt); isTime(created) && isTime(updated) && !created.Nullable && !updated.Nullable {
+		embedded[created.Name], embedded[updated.Name] = true, true
+		fields = append(fields, &generatorField{typ: "aorm.Timestamps"})
+		this.imports[importAorm] = true
+	}
+	if deleted := table.Column(aorm.SoftDeleteColumnDeletedAt); isTime(deleted) && deleted.Nullable {
+		embedded[deleted.Name] = true
+		fields = append(fields, &generatorField{typ: "aorm.SoftDelete"})
+		this.imports[importAorm] = true
+	}
+	for key := range embedded {
+		used[GoName(key)] = true
+	}
+
+	for _, column := range table.Columns {
+		if embedded[column.Name] {
+			continue
+		}
+		field := &generatorField{name: GoName(column.Name)}
+		for used[field.name] {
+			field.name += "_"
+		}
+		used[field.name] = true
+
+		var pkg string
+		if this.isModelRef(table, column.Name) {
+			field.typ, pkg = "bid.BID", importBid
+		} else {
+			field.typ, pkg = GoType(column)
+		}
+		if pkg != "" {
+			this.imports[pkg] = true
+		}
+		if aorm.ToDBName(field.name) != column.Name {
+			field.tags = append(field.tags, "column:"+column.Name)
+		}
+		if table.IsPrimaryKey(column.Name) {
+			field.tags = append(field.tags, "primary_key")
+			if column.AutoIncrement {
+				field.tags = append(field.tags, "auto_increment")
+			}
+		} else if !column.Nullable {
+			field.tags = append(field.tags, "not null")
+		}
+		if column.Size > 0 && strings.TrimPrefix(field.typ, "*") == "string" {
+			field.tags = append(field.tags, "size:"+strconv.Itoa(column.Size))
+		}
+		if column.Default != nil && tagSafe(*column.Default) {
+			field.tags = append(field.tags, "default:"+*column.Default)
+		}
+		fields = append(fields, field)
+		byColumn[column.Name] = field
+	}
+
+	for _, ix := range table.Indexes {
+		tag := "index"
+		if ix.Unique {
+			tag = "unique_index"
+		}
+		for i, column := range ix.Columns {
+			if field := byColumn[column]; field != nil {
+				value := tag + ":" + ix.Name
+				if i == 0 && ix.Where != "" && tagSafe(ix.Where) && !strings.Contains(ix.Where, ",") {
+					value += "=" + ix.Where
+				}
+				field.tags = append(field.tags, value)
+			}
+		}
+	}
+
+	fields = append(fields, this.belongsTo(table, byColumn, used)...)
+	fields = append(fields, this.hasMany(table, name, used)...)
+
+	fmt.Fprintf(w, "// %s is the model of table %q\ntype %s struct {\n", name, table.Name, name)
+	for _, field := range fields {
+		if field.name != "" {
+			fmt.Fprintf(w, "%s ", field.name)
+		}
+		fmt.Fprint(w, field.typ)
+		if len(field.tags) > 0 {
+			fmt.Fprintf(w, " `sql:\"%s\"`", strings.Join(field.tags, ";"))
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "}\n\n")
+	fmt.Fprintf(w, "// TableName returns the table name of %s\nfunc (%s) TableName() string {\nreturn %q\n}\n\n", name, name, table.Name)
+}
+
+func (this *generator) belongsTo(table *Table, byColumn map[string]*generatorField, used map[string]bool) (fields []*generatorField) {
+	for _, fk := range table.ForeignKeys {
+		ref := this.tables[fk.RefTable]
+		if ref == nil || len(fk.Columns) != 1 {
+			continue
+		}
+		fkField := byColumn[fk.Columns[0]]
+		if fkField == nil {
+			continue
+		}
+
+		field := &generatorField{typ: "*" + StructName(ref.Name)}
+		if strings.HasSuffix(fkField.name, "ID") && len(fkField.name) > 2 {
+			field.name = strings.TrimSuffix(fkField.name, "ID")
+		} else {
+			field.name = StructName(ref.Name)
+		}
+		for used[field.name] {
+			field.name += "_"
+		}
+		used[field.name] = true
+
+		if fkField.name != field.name+"ID" {
+			field.tags = append(field.tags, "foreignkey:"+fkField.name)
+		}
+		if len(ref.PrimaryKey) != 1 || ref.PrimaryKey[0] != fk.RefColumns[0] {
+			field.tags = append(field.tags, "association_foreignkey:"+GoName(fk.RefColumns[0]))
+		}
+		fields = append(fields, field)
+	}
+	return
+}
+
+func (this *generator) hasMany(table *Table, name string, used map[string]bool) (fields []*generatorField) {
+	var children []string
+	for childName := range this.tables {
+		children = append(children, childName)
+	}
+	sort.Strings(children)
+
+	for _, childName := range children {
+		var (
+			child = this.tables[childName]
+			refs  []*ForeignKey
+		)
+		for _, fk := range child.ForeignKeys {
+			if fk.RefTable == table.Name && len(fk.Columns) == 1 {
+				refs = append(refs, fk)
+			}
+		}
+		// many references to the same table are ambiguous
+		if len(refs) != 1 || (len(table.PrimaryKey) == 1 && table.PrimaryKey[0] != refs[0].RefColumns[0]) {
+			continue
+		}
+
+		field := &generatorField{
+			name: inflection.Plural(StructName(child.Name)),
+			typ:  "[]" + StructName(child.Name),
+		}
+		if used[field.name] {
+			continue
+		}
+		used[field.name] = true
+		if fkName := GoName(refs[0].Columns[0]); fkName != name+"ID" {
+			field.tags = append(field.tags, "foreignkey:"+fkName)
+		}
+		fields = append(fields, field)
+	}
+	return
+}
+
+// isModel returns if the primary key of table matches the aorm.Model
+func (this *generator) isModel(table *Table) bool {
+	if len(table.PrimaryKey) != 1 || table.PrimaryKey[0] != "id" {
+		return false
+	}
+	switch column := table.Column("id"); column.Type {
+	case "bytea", "blob":
+		return true
+	case "char", "bpchar", "character":
+		return column.Size == 12
+	}
+	return false
+}
+
+// isModelRef returns if column is the single column foreign key to the primary key of table matching aorm.Model
+func (this *generator) isModelRef(table *Table, column string) bool {
+	for _, fk := range table.ForeignKeys {
+		if len(fk.Columns) == 1 && fk.Columns[0] == column {
+			if ref := this.tables[fk.RefTable]; ref != nil && fk.RefColumns[0] == "id" && this.isModel(ref) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isTime(column *Column) bool {
+	if column == nil {
+		return false
+	}
+	typ, _ := GoType(column)
+	return strings.TrimPrefix(typ, "*") == "time.Time"
+}
+
+// tagSafe returns if value can be written into tag settings
+func tagSafe(value string) bool {
+	return !strings.ContainsAny(value, ";`\"\\\n")
+}
@@ -0,0 +1,59 @@
+package reverse_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/aorm/reverse"
+)
+
+func TestGenerate(t *testing.T) {
+	tables := []*reverse.Table{
+		{
+			Name: "authors",
+			Columns: []*reverse.Column{
+				{Name: "id", Type: "bytea"},
+				{Name: "name", Type: "varchar", Size: 50},
+				{Name: "created_at", Type: "timestamptz"},
+				{Name: "updated_at", Type: "timestamptz"},
+				{Name: "deleted_at", Type: "timestamptz", Nullable: true},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []*reverse.Index{{Name: "ux_authors_name", Columns: []string{"name"}, Unique: true}},
+		},
+		{
+			Name: "books",
+			Columns: []*reverse.Column{
+				{Name: "id", Type: "integer", AutoIncrement: true},
+				{Name: "title", Type: "text", Nullable: true},
+				{Name: "writer_id", Type: "bytea"},
+			},
+			PrimaryKey:  []string{"id"},
+			ForeignKeys: []*reverse.ForeignKey{{Name: "fk_books_writer", Columns: []string{"writer_id"}, RefTable: "authors", RefColumns: []string{"id"}}},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := reverse.Generate(&out, "models", tables); err != nil {
+		t.Fatalf("no error should happen when generate, but got %v", err)
+	}
+	src := out.String()
+
+	for _, expected := range []string{
+		"package models",
+		"type Author struct {\n\taorm.Model\n\taorm.Timestamps\n\taorm.SoftDelete\n",
+		"Name  string `sql:\"not null;size:50;unique_index:ux_authors_name\"`",
+		"Books []Book `sql:\"foreignkey:WriterID\"`",
+		"ID       int `sql:\"primary_key;auto_increment\"`",
+		"Title    *string",
+		"WriterID bid.BID `sql:\"not null\"`",
+		"Writer   *Author",
+		"\"github.com/moisespsena-go/bid\"",
+		"func (Book) TableName() string {\n\treturn \"books\"\n}",
+	} {
+		if !strings.Contains(src, expected) {
+			t.Errorf("generated source should contains %q, but got:\n%s", expected, src)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package reverse introspects the tables of existing databases and generates the aorm models of them.
+package reverse
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+// Table is the introspected table
+type Table struct {
+	Name        string
+	Columns     []*Column
+	PrimaryKey  []string
+	Indexes     []*Index
+	ForeignKeys []*ForeignKey
+}
+
+// Column returns the column by name
+func (this *Table) Column(name string) *Column {
+	for _, c := range this.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// IsPrimaryKey returns if column is part of primary key
+func (this *Table) IsPrimaryKey(column string) bool {
+	for _, name := range this.PrimaryKey {
+		if name == column {
+			return true
+		}
+	}
+	return false
+}
+
+// Column is the introspected column
+type Column struct {
+	Name string
+	// Type is the database type, in lower case, without size, e.g. `varchar`, `integer`
+	Type          string
+	Size          int
+	Nullable      bool
+	Default       *string
+	AutoIncrement bool
+}
+
+// Index is the introspected index, except the primary key
+type Index struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	// Where is the condition of partial index
+	Where string
+}
+
+// ForeignKey is the introspected foreign key constraint
+type ForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+	OnDelete   string
+	OnUpdate   string
+}
+
+// Inspect introspects the tables of database. The schema is used by postgres, defaults to `public`. If tables is
+// empty, all tables of database are introspected.
+func Inspect(db *aorm.DB, schema string, tables ...string) (result []*Table, err error) {
+	var inspector interface {
+		tables() ([]string, error)
+		table(name string) (*Table, error)
+	}
+	switch name := db.Dialect().GetName(); name {
+	case "sqlite3":
+		inspector = &sqliteInspector{db.CommonDB()}
+	case "postgres":
+		if schema == "" {
+			schema = "public"
+		}
+		inspector = &postgresInspector{db.CommonDB(), schema}
+	default:
+		return nil, errors.Errorf("reverse: dialect %q is not supported", name)
+	}
+
+	if len(tables) == 0 {
+		if tables, err = inspector.tables(); err != nil {
+			return nil, errors.Wrap(err, "reverse: list tables")
+		}
+	}
+	sort.Strings(tables)
+
+	for _, name := range tables {
+		var table *Table
+		if table, err = inspector.table(name); err != nil {
+			return nil, errors.Wrapf(err, "reverse: inspect table %q", name)
+		}
+		sort.Slice(table.Indexes, func(i, j int) bool {
+			return table.Indexes[i].Name < table.Indexes[j].Name
+		})
+		sort.Slice(table.ForeignKeys, func(i, j int) bool {
+			return table.ForeignKeys[i].Name < table.ForeignKeys[j].Name
+		})
+		result = append(result, table)
+	}
+	return
+}
+
+// splitType splits the database type into lower case name and size, e.g. `VARCHAR(255)` into `varchar` and 255
+func splitType(typ string) (name string, size int) {
+	name = strings.ToLower(strings.TrimSpace(typ))
+	if pos := strings.IndexByte(name, '('); pos > 0 {
+		args := strings.TrimSuffix(name[pos+1:], ")")
+		name = strings.TrimSpace(name[:pos])
+		if comma := strings.IndexByte(args, ','); comma < 0 {
+			for _, c := range args {
+				if c < '0' || c > '9' {
+					return
+				}
+				size = size*10 + int(c-'0')
+			}
+		}
+	}
+	return
+}
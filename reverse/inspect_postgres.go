@@ -0,0 +1,165 @@
+package reverse
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+var postgresActions = map[string]string{
+	"a": "NO ACTION",
+	"r": "RESTRICT",
+	"c": "CASCADE",
+	"n": "SET NULL",
+	"d": "SET DEFAULT",
+}
+
+type postgresInspector struct {
+	db     aorm.SQLCommon
+	schema string
+}
+
+func (this *postgresInspector) tables() (tables []string, err error) {
+	rows, err := this.db.Query(`SELECT table_name FROM information_schema.tables
+	WHERE table_schema = $1 AND table_type = 'BASE TABLE' ORDER BY table_name`, this.schema)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+func (this *postgresInspector) regclass(table string) string {
+	return aorm.QuotePath(aorm.QuoteRuner('"'), this.schema) + "." + aorm.Quote(aorm.QuoteRuner('"'), table)
+}
+
+func (this *postgresInspector) table(name string) (table *Table, err error) {
+	table = &Table{Name: name}
+	if err = this.columns(table); err != nil {
+		return
+	}
+	if err = this.primaryKey(table); err != nil {
+		return
+	}
+	if err = this.indexes(table); err != nil {
+		return
+	}
+	err = this.foreignKeys(table)
+	return
+}
+
+func (this *postgresInspector) columns(table *Table) (err error) {
+	rows, err := this.db.Query(`SELECT column_name, udt_name, is_nullable, column_default, character_maximum_length
+	FROM information_schema.columns WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position`, this.schema, table.Name)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			name, typ, nullable string
+			defaultValue        sql.NullString
+			size                sql.NullInt64
+		)
+		if err = rows.Scan(&name, &typ, &nullable, &defaultValue, &size); err != nil {
+			return
+		}
+		column := &Column{Name: name, Type: strings.ToLower(typ), Size: int(size.Int64), Nullable: nullable == "YES"}
+		if defaultValue.Valid {
+			if strings.HasPrefix(defaultValue.String, "nextval(") {
+				column.AutoIncrement = true
+			} else {
+				column.Default = &defaultValue.String
+			}
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	return rows.Err()
+}
+
+func (this *postgresInspector) primaryKey(table *Table) (err error) {
+	rows, err := this.db.Query(`SELECT a.attname FROM pg_index i
+	CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY k(attnum, ord)
+	JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+	WHERE i.indrelid = $1::regclass AND i.indisprimary ORDER BY k.ord`, this.regclass(table.Name))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return
+		}
+		table.PrimaryKey = append(table.PrimaryKey, name)
+	}
+	return rows.Err()
+}
+
+func (this *postgresInspector) indexes(table *Table) (err error) {
+	rows, err := this.db.Query(`SELECT c.relname, i.indisunique, coalesce(pg_get_expr(i.indpred, i.indrelid), ''),
+		string_agg(a.attname, ',' ORDER BY k.ord)
+	FROM pg_index i
+	JOIN pg_class c ON c.oid = i.indexrelid
+	CROSS JOIN LATERAL unnest(i.indkey::int2[]) WITH ORDINALITY k(attnum, ord)
+	JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = k.attnum
+	WHERE i.indrelid = $1::regclass AND NOT i.indisprimary
+	GROUP BY c.relname, i.indisunique, i.indpred, i.indrelid`, this.regclass(table.Name))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			ix      = &Index{}
+			columns string
+		)
+		if err = rows.Scan(&ix.Name, &ix.Unique, &ix.Where, &columns); err != nil {
+			return
+		}
+		ix.Columns = strings.Split(columns, ",")
+		table.Indexes = append(table.Indexes, ix)
+	}
+	return rows.Err()
+}
+
+func (this *postgresInspector) foreignKeys(table *Table) (err error) {
+	rows, err := this.db.Query(`SELECT con.conname, string_agg(a.attname, ',' ORDER BY k.ord), cl.relname,
+		string_agg(af.attname, ',' ORDER BY k.ord), con.confupdtype, con.confdeltype
+	FROM pg_constraint con
+	JOIN pg_class cl ON cl.oid = con.confrelid
+	CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY k(attnum, fattnum, ord)
+	JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+	JOIN pg_attribute af ON af.attrelid = con.confrelid AND af.attnum = k.fattnum
+	WHERE con.conrelid = $1::regclass AND con.contype = 'f'
+	GROUP BY con.conname, cl.relname, con.confupdtype, con.confdeltype`, this.regclass(table.Name))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			fk                  = &ForeignKey{}
+			columns, refColumns string
+			onUpdate, onDelete  string
+		)
+		if err = rows.Scan(&fk.Name, &columns, &fk.RefTable, &refColumns, &onUpdate, &onDelete); err != nil {
+			return
+		}
+		fk.Columns = strings.Split(columns, ",")
+		fk.RefColumns = strings.Split(refColumns, ",")
+		fk.OnUpdate = postgresActions[onUpdate]
+		fk.OnDelete = postgresActions[onDelete]
+		table.ForeignKeys = append(table.ForeignKeys, fk)
+	}
+	return rows.Err()
+}
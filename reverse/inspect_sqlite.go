@@ -0,0 +1,189 @@
+package reverse
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type sqliteInspector struct {
+	db aorm.SQLCommon
+}
+
+func (this *sqliteInspector) tables() (tables []string, err error) {
+	rows, err := this.db.Query("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return
+		}
+		tables = append(tables, name)
+	}
+	return tables, rows.Err()
+}
+
+func (this *sqliteInspector) quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (this *sqliteInspector) table(name string) (table *Table, err error) {
+	table = &Table{Name: name}
+	if err = this.columns(table); err != nil {
+		return
+	}
+	if err = this.indexes(table); err != nil {
+		return
+	}
+	err = this.foreignKeys(table)
+	return
+}
+
+func (this *sqliteInspector) columns(table *Table) (err error) {
+	rows, err := this.db.Query("PRAGMA table_info(" + this.quote(table.Name) + ")")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var pks = map[int]string{}
+	for rows.Next() {
+		var (
+			cid, notNull, pk int
+			name, typ        string
+			defaultValue     sql.NullString
+		)
+		if err = rows.Scan(&cid, &name, &typ, &notNull, &defaultValue, &pk); err != nil {
+			return
+		}
+		column := &Column{Name: name, Nullable: notNull == 0 && pk == 0}
+		column.Type, column.Size = splitType(typ)
+		if defaultValue.Valid {
+			column.Default = &defaultValue.String
+		}
+		if pk > 0 {
+			pks[pk] = name
+		}
+		table.Columns = append(table.Columns, column)
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for i := 1; i <= len(pks); i++ {
+		table.PrimaryKey = append(table.PrimaryKey, pks[i])
+	}
+	if len(table.PrimaryKey) == 1 {
+		// INTEGER PRIMARY KEY is alias of rowid
+		if column := table.Column(table.PrimaryKey[0]); column.Type == "integer" {
+			column.AutoIncrement = true
+		}
+	}
+	return
+}
+
+func (this *sqliteInspector) indexes(table *Table) (err error) {
+	rows, err := this.db.Query("PRAGMA index_list(" + this.quote(table.Name) + ")")
+	if err != nil {
+		return
+	}
+
+	var indexes []*Index
+	for rows.Next() {
+		var (
+			seq, unique, partial int
+			name, origin         string
+		)
+		if err = rows.Scan(&seq, &name, &unique, &origin, &partial); err != nil {
+			rows.Close()
+			return
+		}
+		if origin == "pk" {
+			continue
+		}
+		indexes = append(indexes, &Index{Name: name, Unique: unique == 1})
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for _, ix := range indexes {
+		if rows, err = this.db.Query("PRAGMA index_info(" + this.quote(ix.Name) + ")"); err != nil {
+			return
+		}
+		for rows.Next() {
+			var (
+				seqNo, cid int
+				name       string
+			)
+			if err = rows.Scan(&seqNo, &cid, &name); err != nil {
+				rows.Close()
+				return
+			}
+			ix.Columns = append(ix.Columns, name)
+		}
+		rows.Close()
+
+		var ddl sql.NullString
+		if err = this.db.QueryRow("SELECT sql FROM sqlite_master WHERE type = 'index' AND name = ?", ix.Name).Scan(&ddl); err != nil && err != sql.ErrNoRows {
+			return
+		}
+		err = nil
+		if pos := strings.Index(strings.ToUpper(ddl.String), " WHERE "); pos > 0 {
+			ix.Where = strings.TrimSpace(ddl.String[pos+7:])
+		}
+		table.Indexes = append(table.Indexes, ix)
+	}
+	return
+}
+
+func (this *sqliteInspector) foreignKeys(table *Table) (err error) {
+	rows, err := this.db.Query("PRAGMA foreign_key_list(" + this.quote(table.Name) + ")")
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	var byID = map[int]*ForeignKey{}
+	for rows.Next() {
+		var (
+			id, seq                                 int
+			refTable, from, onUpdate, onDelete, mat string
+			to                                      sql.NullString
+		)
+		if err = rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &mat); err != nil {
+			return
+		}
+		fk := byID[id]
+		if fk == nil {
+			fk = &ForeignKey{
+				Name:     "fk_" + table.Name + "_" + refTable + "_" + from,
+				RefTable: refTable,
+				OnUpdate: onUpdate,
+				OnDelete: onDelete,
+			}
+			byID[id] = fk
+			table.ForeignKeys = append(table.ForeignKeys, fk)
+		}
+		fk.Columns = append(fk.Columns, from)
+		// missing referenced column references the primary key
+		fk.RefColumns = append(fk.RefColumns, to.String)
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
+
+	for _, fk := range table.ForeignKeys {
+		for i, column := range fk.RefColumns {
+			if column == "" {
+				fk.RefColumns[i] = "id"
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package aorm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const OptKeySchema = "aorm:schema"
+
+// only match simple table names like `users`, `user_languages`
+var tableNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z\d_$]*$`)
+
+// WithSchema qualifies every generated table reference, including join tables, children tables and foreign
+// key targets, with the schema, e.g. for schema-per-tenant databases. Columns still are referenced by table name.
+//
+//	db.WithSchema("tenant_42").Find(&users) // SELECT "users".* FROM "tenant_42"."users"
+func (s *DB) WithSchema(schema string) *DB {
+	return s.Set(OptKeySchema, schema)
+}
+
+// Schema returns the schema set by WithSchema
+func (s *DB) Schema() string {
+	if v, ok := s.Get(OptKeySchema); ok && v != nil {
+		return v.(string)
+	}
+	return ""
+}
+
+// Schema returns the schema set by `DB.WithSchema`
+func (scope *Scope) Schema() string {
+	return scope.db.Schema()
+}
+
+// QualifyTableName returns the table name qualified with the scope schema. Names of other forms, like
+// already qualified names or sql expressions, are returned as is.
+func (scope *Scope) QualifyTableName(name string) string {
+	if schema := scope.Schema(); schema != "" && tableNameRegexp.MatchString(name) {
+		return schema + "." + name
+	}
+	return name
+}
+
+// QuotedQualifiedTableName return quoted table name qualified with the scope schema, used as table reference
+// of FROM, JOIN, INSERT, UPDATE, DELETE and DDL statements
+func (scope *Scope) QuotedQualifiedTableName() string {
+	return scope.Quote(scope.QualifyTableName(scope.TableName()))
+}
+
+// SplitSchemaTableName splits the qualified table name into schema and table name
+func SplitSchemaTableName(name string) (schema, tableName string) {
+	if pos := strings.LastIndexByte(name, '.'); pos > 0 {
+		return name[0:pos], name[pos+1:]
+	}
+	return "", name
+}
+
+// CreateSchema creates the schema if does not exists
+func (s *DB) CreateSchema(schema string) *DB {
+	return s.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %v", Quote(s.Dialect(), schema)))
+}
+
+// Register register models migrated by MigrateSchemas
+func (this *Migrator) Register(models ...interface{}) *Migrator {
+	this.models = append(this.models, models...)
+	return this
+}
+
+// MigrateSchemas migrates all registered models into each schema, creating the schema if missing.
+//
+//	db.Migrator().Register(&User{}, &Order{}).MigrateSchemas("tenant_1", "tenant_2")
+func (this *Migrator) MigrateSchemas(schemas ...string) error {
+	return this.Migrate(func() error {
+		db := this.db
+		for _, schema := range schemas {
+			if err := db.CreateSchema(schema).Error; err != nil {
+				this.failed = true
+				return errors.Wrapf(err, "create schema %q", schema)
+			}
+			if err := db.WithSchema(schema).autoMigrate(this.models...).Error; err != nil {
+				this.failed = true
+				return errors.Wrapf(err, "migrate schema %q", schema)
+			}
+		}
+		return nil
+	})
+}
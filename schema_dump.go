@@ -0,0 +1,155 @@
+package aorm
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DumpSchema returns the DDL script that creates the models, with its children tables, join tables, indexes,
+// full text search structures and foreign keys, for the dialect, without a database connection.
+//
+//	script, err := aorm.DumpSchema("postgres", &User{}, &Order{})
+func DumpSchema(dialectName string, models ...interface{}) (string, error) {
+	return FakeDB(dialectName).DumpSchema(models...)
+}
+
+// DumpSchema returns the DDL script that creates the models for dialect of db. The tables are qualified with
+// the schema of db, if set by WithSchema.
+//
+// The tables are created in dependency order of foreign keys, followed by join tables, indexes and foreign
+// keys. The CreateTable before callbacks of models are called, so they can change the create table statement.
+func (s *DB) DumpSchema(models ...interface{}) (script string, err error) {
+	var (
+		modelStructs []*ModelStruct
+		seen         = map[*ModelStruct]bool{}
+		add          func(ms *ModelStruct)
+	)
+	add = func(ms *ModelStruct) {
+		if seen[ms] {
+			return
+		}
+		seen[ms] = true
+		modelStructs = append(modelStructs, ms)
+		for _, child := range ms.Children {
+			add(child)
+		}
+		for _, child := range ms.HasManyChildren {
+			add(child)
+		}
+	}
+	for _, model := range models {
+		ms, err := s.ModelStructStorage().GetOrNew(model)
+		if err != nil {
+			return "", errors.Wrapf(err, "dump schema of %T", model)
+		}
+		add(ms)
+	}
+
+	var (
+		tables, joinTables, indexes, foreignKeys []string
+		joinTablesSeen                           = map[string]bool{}
+		foreignKeysSeen                          = map[string]bool{}
+		scopes                                   = map[*ModelStruct]*Scope{}
+	)
+
+	for _, ms := range sortModelStructsByForeignKeys(s, modelStructs) {
+		scope := s.NewModelScope(ms, ms.Value)
+		scopes[ms] = scope
+
+		scope.Query.Query = scope.createTableSQL()
+		ms.TypeCallbacks.TypeRegistrator.Call("CreateTable", Before, scope, nil)
+		if scope.HasError() {
+			return "", scope.db.Error
+		}
+		tables = append(tables, scope.Query.Query)
+		scope.Query.Query = ""
+
+		for _, field := range ms.Fields {
+			if rel := field.Relationship; rel != nil && rel.JoinTableHandler != nil {
+				if name := rel.JoinTableHandler.Table(s); !joinTablesSeen[name] {
+					joinTablesSeen[name] = true
+					joinTables = append(joinTables, scope.createJoinTableSQL(field))
+				}
+			}
+		}
+
+		tableName := scope.QualifyTableName(scope.TableName())
+		for _, ixs := range []IndexMap{ms.Indexes, ms.UniqueIndexes} {
+			var sqls []string
+			for _, ix := range ixs {
+				_, sql := ix.SqlCreate(s.dialect, tableName)
+				sqls = append(sqls, sql)
+			}
+			sort.Strings(sqls)
+			indexes = append(indexes, sqls...)
+		}
+		if ms.FullText != nil {
+			for _, stmt := range scope.fullTextSQL() {
+				indexes = append(indexes, stmt.sql)
+			}
+		}
+	}
+
+	for _, ms := range modelStructs {
+		for _, fk := range ms.ForeignKeys {
+			def := fk.Definition(scopes[ms])
+			if !foreignKeysSeen[def.Name] {
+				foreignKeysSeen[def.Name] = true
+				foreignKeys = append(foreignKeys, def.Query(s.dialect))
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, group := range [][]string{tables, joinTables, indexes, foreignKeys} {
+		for _, sql := range group {
+			b.WriteString(strings.TrimSuffix(strings.TrimSpace(sql), ";"))
+			b.WriteString(";\n")
+		}
+		if len(group) > 0 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// sortModelStructsByForeignKeys sorts the model structs so the referenced tables comes before the referencing
+// tables. Cyclic references keeps the given order.
+func sortModelStructsByForeignKeys(db *DB, modelStructs []*ModelStruct) (sorted []*ModelStruct) {
+	var (
+		byTable = map[string]*ModelStruct{}
+		deps    = map[*ModelStruct][]*ModelStruct{}
+		state   = map[*ModelStruct]bool{}
+		visit   func(ms *ModelStruct)
+	)
+	for _, ms := range modelStructs {
+		byTable[db.NewModelScope(ms, ms.Value).TableName()] = ms
+	}
+	for _, ms := range modelStructs {
+		scope := db.NewModelScope(ms, ms.Value)
+		for _, fk := range ms.ForeignKeys {
+			def := fk.Definition(scope)
+			_, src := SplitSchemaTableName(def.SrcTableName)
+			_, dst := SplitSchemaTableName(def.DstTableName)
+			if srcModel, dstModel := byTable[src], byTable[dst]; srcModel != nil && dstModel != nil && src != dst {
+				deps[srcModel] = append(deps[srcModel], dstModel)
+			}
+		}
+	}
+	visit = func(ms *ModelStruct) {
+		if state[ms] {
+			return
+		}
+		state[ms] = true
+		for _, dep := range deps[ms] {
+			visit(dep)
+		}
+		sorted = append(sorted, ms)
+	}
+	for _, ms := range modelStructs {
+		visit(ms)
+	}
+	return
+}
@@ -0,0 +1,53 @@
+package aorm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type DumpAuthor struct {
+	ID   int
+	Name string `sql:"unique_index"`
+}
+
+type DumpTag struct {
+	ID   int
+	Name string
+}
+
+type DumpBook struct {
+	ID       int
+	Title    string `sql:"index"`
+	AuthorID int
+	Author   *DumpAuthor
+	Tags     []DumpTag `aorm:"many2many:dump_book_tags"`
+}
+
+func TestDumpSchema(t *testing.T) {
+	for _, dialect := range []string{"postgres", "mysql", "sqlite3"} {
+		script, err := aorm.DumpSchema(dialect, &DumpBook{}, &DumpAuthor{}, &DumpTag{})
+		if err != nil {
+			t.Fatalf("%s: no error should happen when dump schema, but got %v", dialect, err)
+		}
+
+		var positions []int
+		for _, table := range []string{"aorm_test_dump_books", "aorm_test_dump_authors", "aorm_test_dump_tags", "dump_book_tags"} {
+			pos := strings.Index(script, "CREATE TABLE "+aorm.Quote(aorm.FakeDB(dialect).Dialect(), table))
+			if pos < 0 {
+				t.Errorf("%s: should create table %v, but got:\n%s", dialect, table, script)
+			}
+			positions = append(positions, pos)
+		}
+		if positions[3] < positions[0] || positions[3] < positions[2] {
+			t.Errorf("%s: join tables should be created after model tables, but got:\n%s", dialect, script)
+		}
+		if !strings.Contains(script, "CREATE UNIQUE INDEX") || !strings.Contains(script, "CREATE INDEX") {
+			t.Errorf("%s: should create indexes, but got:\n%s", dialect, script)
+		}
+		if index := strings.Index(script, "CREATE INDEX"); index < positions[3] {
+			t.Errorf("%s: indexes should be created after tables, but got:\n%s", dialect, script)
+		}
+	}
+}
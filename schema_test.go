@@ -0,0 +1,84 @@
+package aorm_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+type SchemaAuthor struct {
+	ID    int
+	Name  string
+	Books []SchemaBook
+	Tags  []SchemaTag `aorm:"many2many:schema_author_tags"`
+}
+
+func (SchemaAuthor) TableName() string {
+	return "schema_authors"
+}
+
+type SchemaBook struct {
+	ID             int
+	Title          string
+	SchemaAuthorID int
+}
+
+func (SchemaBook) TableName() string {
+	return "schema_books"
+}
+
+type SchemaTag struct {
+	ID   int
+	Name string
+}
+
+func (SchemaTag) TableName() string {
+	return "schema_tags"
+}
+
+func TestMigrateSchemas(t *testing.T) {
+	if dialect := os.Getenv("AORM_DIALECT"); dialect != "postgres" {
+		t.Skip("Skipping this because only postgres supports schema per tenant")
+	}
+
+	schemas := []string{"aorm_tenant_1", "aorm_tenant_2"}
+	for _, schema := range schemas {
+		DB.Exec(fmt.Sprintf(`DROP SCHEMA IF EXISTS "%v" CASCADE`, schema))
+	}
+
+	if err := DB.Migrator().Register(&SchemaAuthor{}, &SchemaBook{}, &SchemaTag{}).MigrateSchemas(schemas...); err != nil {
+		t.Fatalf("no error should happen when migrate schemas, but got %v", err)
+	}
+
+	for _, schema := range schemas {
+		for _, table := range []string{"schema_authors", "schema_books", "schema_tags", "schema_author_tags"} {
+			if !DB.Dialect().HasTable(schema + "." + table) {
+				t.Errorf("table %v should be created in schema %v", table, schema)
+			}
+		}
+	}
+
+	tenant1, tenant2 := DB.WithSchema(schemas[0]), DB.WithSchema(schemas[1])
+
+	author := SchemaAuthor{Name: "author", Books: []SchemaBook{{Title: "book"}}, Tags: []SchemaTag{{Name: "tag"}}}
+	if err := tenant1.Save(&author).Error; err != nil {
+		t.Fatalf("no error should happen when save in schema, but got %v", err)
+	}
+
+	var count int
+	if tenant2.Model(&SchemaAuthor{}).Count(&count); count != 0 {
+		t.Errorf("author should not exists in other schema, but got %v", count)
+	}
+
+	var found SchemaAuthor
+	if err := tenant1.Preload("Books").Preload("Tags").First(&found, author.ID).Error; err != nil {
+		t.Fatalf("no error should happen when find in schema, but got %v", err)
+	}
+	if len(found.Books) != 1 || len(found.Tags) != 1 {
+		t.Errorf("children and many2many should be preloaded from schema, but got %v and %v", found.Books, found.Tags)
+	}
+
+	if err := tenant1.Delete(&found).Error; err != nil {
+		t.Errorf("no error should happen when delete in schema, but got %v", err)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/pkg/errors"
 )
 
 type Operation string
@@ -38,6 +40,9 @@ type Scope struct {
 	Operation            Operation
 	fieldsScanerCallback []func(f *Field)
 	fixedColumns         bool
+	modelScopesApplied   bool
+	modelScopeSearches   []*search
+	tree                 *scopeTree
 }
 
 func (scope *Scope) InlinePreloads() *InlinePreloads {
@@ -329,6 +334,9 @@ func (scope *Scope) RealTableName() (name string) {
 		}
 	}
 
+	if scope.Value == nil {
+		return ""
+	}
 	if tabler, ok := scope.Value.(TableNamer); ok {
 		return tabler.TableName()
 	}
@@ -361,9 +369,20 @@ func (scope *Scope) QuotedTableName() (name string) {
 // fromSql return from sql
 func (scope *Scope) fromSql() (query string) {
 	if s := scope.Search; s != nil {
-		if s.from == "" {
+		if s.fromQuery != nil {
+			q, err := s.fromQuery.Build(scope)
+			if err != nil {
+				scope.Err(errors.Wrap(err, "build from sub query"))
+			}
+			query = "(" + q + ")"
+			if s.tableAlias == "" {
+				query += " AS " + scope.Quote(scope.RealTableName())
+			}
+		} else if s.from == "" {
 			if s.tableName == "" {
-				query = scope.Quote(scope.RealTableName())
+				query = scope.Quote(scope.QualifyTableName(scope.RealTableName()))
+			} else if qualified := scope.QualifyTableName(s.tableName); qualified != s.tableName {
+				query = scope.Quote(qualified)
 			} else {
 				query = s.tableName
 			}
@@ -376,11 +395,12 @@ func (scope *Scope) fromSql() (query string) {
 		return query
 	}
 
-	return scope.Quote(scope.RealTableName())
+	return scope.Quote(scope.QualifyTableName(scope.RealTableName()))
 }
 
 // CombinedConditionSql return combined condition sql
 func (scope *Scope) CombinedConditionSql() string {
+	scope.applyModelScopes()
 	joinSQL := scope.joinsSQL()
 	whereSQL := scope.whereSQL()
 	if scope.Search.raw {
@@ -473,6 +493,7 @@ func (scope *Scope) Begin() *Scope {
 	if !scope.GetBool("aorm:disable_scope_transaction") {
 		if db, ok := scope.SQLDB().(sqlDb); ok {
 			if tx, err := db.Begin(); err == nil {
+				scope.db.trackTxRecords(scope.db)
 				scope.db.db = interface{}(tx).(SQLCommon)
 				scope.InstanceSet("aorm:started_transaction", true)
 			}
@@ -487,9 +508,14 @@ func (scope *Scope) CommitOrRollback() *Scope {
 		if db, ok := scope.db.db.(sqlTx); ok {
 			if scope.HasError() {
 				db.Rollback()
+				scope.db.flushTxRecords(false)
+			} else if err := db.Commit(); err != nil {
+				scope.Err(err)
+				scope.db.flushTxRecords(false)
 			} else {
-				scope.Err(db.Commit())
+				scope.Err(scope.db.flushTxRecords(true))
 			}
+			delete(scope.db.values, optKeyTxRecords)
 			scope.db.db = scope.db.parent.db
 		}
 	}
@@ -788,7 +814,7 @@ func (scope *Scope) trace(t time.Time) {
 }
 
 func (scope *Scope) changeableField(field *Field) bool {
-	if field.IsReadOnly {
+	if field.IsReadOnly || field.Generated != nil {
 		return false
 	}
 	if selectAttrs := scope.SelectAttrs(); len(selectAttrs) > 0 {
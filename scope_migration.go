@@ -2,59 +2,131 @@ package aorm
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 func (scope *Scope) createJoinTable(field *StructField) {
 	if relationship := field.Relationship; relationship != nil && relationship.JoinTableHandler != nil {
 		joinTableHandler := relationship.JoinTableHandler
-		joinTable := joinTableHandler.Table(scope.db)
+		joinTable := scope.QualifyTableName(joinTableHandler.Table(scope.db))
 		if !scope.Dialect().HasTable(joinTable) {
-			toStruct := StructOf(field.Struct.Type)
-
-			var sqlTypes, primaryKeys []string
-			for idx, fieldName := range relationship.ForeignFieldNames {
-				if field, ok := scope.modelStruct.FieldsByName[fieldName]; ok {
-					foreignKeyStruct := field.clone()
-					foreignKeyStruct.IsPrimaryKey = false
-					foreignKeyStruct.TagSettings["IS_JOINTABLE_FOREIGNKEY"] = "true"
-					delete(foreignKeyStruct.TagSettings, "AUTO_INCREMENT")
-					sqlTypes = append(sqlTypes, scope.Quote(relationship.ForeignDBNames[idx])+" "+scope.Dialect().DataTypeOf(foreignKeyStruct.Structure()))
-					primaryKeys = append(primaryKeys, scope.Quote(relationship.ForeignDBNames[idx]))
-				}
+			// TODO: implements auditor
+			scope.Err(scope.NewDB().Table(joinTable).Exec(scope.createJoinTableSQL(field)).Error)
+		} else {
+			fields := joinTablePayloadFields(joinTableHandler)
+			if field := joinTableTenantField(joinTableHandler); field != nil {
+				fields = append(fields, field)
 			}
-
-			for idx, fieldName := range relationship.AssociationForeignFieldNames {
-				if field, ok := toStruct.FieldsByName[fieldName]; ok {
-					foreignKeyStruct := field.clone()
-					foreignKeyStruct.IsPrimaryKey = false
-					foreignKeyStruct.TagSettings["IS_JOINTABLE_FOREIGNKEY"] = "true"
-					delete(foreignKeyStruct.TagSettings, "AUTO_INCREMENT")
-					sqlTypes = append(sqlTypes, scope.Quote(relationship.AssociationForeignDBNames[idx])+" "+scope.Dialect().DataTypeOf(foreignKeyStruct.Structure()))
-					primaryKeys = append(primaryKeys, scope.Quote(relationship.AssociationForeignDBNames[idx]))
+			for _, field := range fields {
+				if !scope.Dialect().HasColumn(joinTable, field.DBName) {
+					ddl := fmt.Sprintf("ALTER TABLE %v ADD %v %v;", scope.Quote(joinTable), scope.Quote(field.DBName), scope.Dialect().DataTypeOf(field.Structure()))
+					scope.Err(scope.NewDB().Table(joinTable).Exec(ddl).Error)
 				}
 			}
+		}
+		scope.NewDB().Table(joinTable).AutoMigrate(joinTableHandler)
+	}
+}
 
-			ddl := fmt.Sprintf("CREATE TABLE %v (%v, PRIMARY KEY (%v))%s",
-				scope.Quote(joinTable), strings.Join(sqlTypes, ","),
-				strings.Join(primaryKeys, ","),
-				scope.getTableOptions())
+// createJoinTableSQL returns the create table statement of many to many field join table
+func (scope *Scope) createJoinTableSQL(field *StructField) string {
+	var (
+		relationship     = field.Relationship
+		joinTableHandler = relationship.JoinTableHandler
+		joinTable        = scope.QualifyTableName(joinTableHandler.Table(scope.db))
+		toStruct         = StructOf(field.Struct.Type)
+		sqlTypes         []string
+		primaryKeys      []string
+	)
+
+	for idx, fieldName := range relationship.ForeignFieldNames {
+		if field, ok := scope.modelStruct.FieldsByName[fieldName]; ok {
+			foreignKeyStruct := field.clone()
+			foreignKeyStruct.IsPrimaryKey = false
+			foreignKeyStruct.TagSettings["IS_JOINTABLE_FOREIGNKEY"] = "true"
+			delete(foreignKeyStruct.TagSettings, "AUTO_INCREMENT")
+			sqlTypes = append(sqlTypes, scope.Quote(relationship.ForeignDBNames[idx])+" "+scope.Dialect().DataTypeOf(foreignKeyStruct.Structure()))
+			primaryKeys = append(primaryKeys, scope.Quote(relationship.ForeignDBNames[idx]))
+		}
+	}
 
-			// TODO: implements auditor
-			scope.Err(scope.NewDB().Table(joinTable).Exec(ddl).Error)
+	for idx, fieldName := range relationship.AssociationForeignFieldNames {
+		if field, ok := toStruct.FieldsByName[fieldName]; ok {
+			foreignKeyStruct := field.clone()
+			foreignKeyStruct.IsPrimaryKey = false
+			foreignKeyStruct.TagSettings["IS_JOINTABLE_FOREIGNKEY"] = "true"
+			delete(foreignKeyStruct.TagSettings, "AUTO_INCREMENT")
+			sqlTypes = append(sqlTypes, scope.Quote(relationship.AssociationForeignDBNames[idx])+" "+scope.Dialect().DataTypeOf(foreignKeyStruct.Structure()))
+			primaryKeys = append(primaryKeys, scope.Quote(relationship.AssociationForeignDBNames[idx]))
 		}
-		scope.NewDB().Table(joinTable).AutoMigrate(joinTableHandler)
 	}
+
+	if relationship.PolymorphicDBName != "" {
+		typeStruct := &FieldStructure{Type: reflect.TypeOf(""), TagSettings: map[string]string{"SIZE": "255"}}
+		sqlTypes = append(sqlTypes, scope.Quote(relationship.PolymorphicDBName)+" "+scope.Dialect().DataTypeOf(typeStruct))
+		primaryKeys = append(primaryKeys, scope.Quote(relationship.PolymorphicDBName))
+	}
+
+	for _, field := range joinTablePayloadFields(joinTableHandler) {
+		sqlTypes = append(sqlTypes, scope.Quote(field.DBName)+" "+scope.Dialect().DataTypeOf(field.Structure()))
+	}
+
+	if field := joinTableTenantField(joinTableHandler); field != nil {
+		sqlTypes = append(sqlTypes, scope.Quote(field.DBName)+" "+scope.Dialect().DataTypeOf(field.Structure()))
+	}
+
+	return fmt.Sprintf("CREATE TABLE %v (%v, PRIMARY KEY (%v))%s",
+		scope.Quote(joinTable), strings.Join(sqlTypes, ","),
+		strings.Join(primaryKeys, ","),
+		scope.getTableOptions())
+}
+
+func joinTablePayloadFields(handler JoinTableHandlerInterface) []*StructField {
+	if payloader, ok := handler.(JoinTablePayloader); ok {
+		return payloader.PayloadFields()
+	}
+	return nil
 }
 
 func (scope *Scope) createTable() *Scope {
+	Struct := scope.Struct()
+	for _, field := range Struct.Fields {
+		scope.createJoinTable(field)
+	}
+
+	scope.Query.Query = scope.createTableSQL()
+	Struct.TypeCallbacks.TypeRegistrator.Call("CreateTable", Before, scope, nil)
+	if scope.HasError() {
+		return scope
+	}
+	scope.Raw(scope.Query.Query).Exec()
+	if scope.HasError() {
+		return scope
+	}
+	Struct.TypeCallbacks.TypeRegistrator.Call("CreateTable", After, scope, nil)
+	if scope.HasError() {
+		return scope
+	}
+	scope.Query.Query = ""
+
+	scope.autoIndex()
+	scope.autoFullText()
+	scope.autoForeignKeys()
+	scope.createChildrenTables()
+	return scope
+}
+
+// createTableSQL returns the create table statement of scope model
+func (scope *Scope) createTableSQL() string {
 	var tags []string
 	var primaryKeys []string
 	var primaryKeyInColumnType = false
-	Struct := scope.Struct()
-	for _, field := range Struct.Fields {
+	for _, field := range scope.Struct().Fields {
 		if field.IsNormal {
-			sqlTag := scope.Dialect().DataTypeOf(field.Structure())
+			sqlTag := scope.columnSQLOf(field)
 
 			// Check if the primary key constraint was specified as
 			// part of the column type. If so, we can only support
@@ -69,7 +141,6 @@ func (scope *Scope) createTable() *Scope {
 		if field.IsPrimaryKey {
 			primaryKeys = append(primaryKeys, scope.Quote(field.DBName))
 		}
-		scope.createJoinTable(field)
 	}
 
 	var primaryKeyStr string
@@ -77,42 +148,46 @@ func (scope *Scope) createTable() *Scope {
 		primaryKeyStr = fmt.Sprintf(", PRIMARY KEY (%v)", strings.Join(primaryKeys, ","))
 	}
 
-	scope.Query.Query = fmt.Sprintf("CREATE TABLE %v (%v %v)%s", scope.QuotedTableName(), strings.Join(tags, ","), primaryKeyStr, scope.getTableOptions())
-	Struct.TypeCallbacks.TypeRegistrator.Call("CreateTable", Before, scope, nil)
-	if scope.HasError() {
-		return scope
+	return fmt.Sprintf("CREATE TABLE %v (%v %v)%s", scope.QuotedQualifiedTableName(), strings.Join(tags, ","), primaryKeyStr, scope.getTableOptions())
+}
+
+// columnSQLOf returns the column definition of field, without name
+func (scope *Scope) columnSQLOf(field *StructField) string {
+	if field.Generated == nil {
+		return scope.Dialect().DataTypeOf(field.Structure())
 	}
-	scope.Raw(scope.Query.Query).Exec()
-	if scope.HasError() {
-		return scope
+	// the constraints of generated column follow the expression, and it can not have default value
+	structure := field.Structure()
+	structure.TagSettings = map[string]string{}
+	for key, value := range field.TagSettings {
+		switch key {
+		case "NOT NULL", "UNIQUE", "DEFAULT":
+		default:
+			structure.TagSettings[key] = value
+		}
 	}
-	Struct.TypeCallbacks.TypeRegistrator.Call("CreateTable", After, scope, nil)
-	if scope.HasError() {
-		return scope
+	sql := scope.Dialect().GeneratedColumnSQL(scope.Dialect().DataTypeOf(structure), field.Generated.Expr, field.Generated.Stored)
+	if _, ok := field.TagSettings["NOT NULL"]; ok {
+		sql += " NOT NULL"
 	}
-	scope.Query.Query = ""
-
-	scope.autoIndex()
-	scope.autoForeignKeys()
-	scope.createChildrenTables()
-	return scope
+	return sql
 }
 
 func (scope *Scope) dropTable() *Scope {
-	scope.Raw(fmt.Sprintf("DROP TABLE %v%s", scope.QuotedTableName(), scope.getTableOptions())).Exec()
+	scope.Raw(fmt.Sprintf("DROP TABLE %v%s", scope.QuotedQualifiedTableName(), scope.getTableOptions())).Exec()
 	return scope
 }
 
 func (scope *Scope) modifyColumn(column string, typ string) {
-	scope.db.AddError(scope.Dialect().ModifyColumn(scope.QuotedTableName(), scope.Quote(column), typ))
+	scope.db.AddError(scope.Dialect().ModifyColumn(scope.QuotedQualifiedTableName(), scope.Quote(column), typ))
 }
 
 func (scope *Scope) dropColumn(column string) {
-	scope.Raw(fmt.Sprintf("ALTER TABLE %v DROP COLUMN %v", scope.QuotedTableName(), scope.Quote(column))).Exec()
+	scope.Raw(fmt.Sprintf("ALTER TABLE %v DROP COLUMN %v", scope.QuotedQualifiedTableName(), scope.Quote(column))).Exec()
 }
 
 func (scope *Scope) addIndex(unique bool, indexName string, column ...string) {
-	if scope.Dialect().HasIndex(scope.TableName(), indexName) {
+	if scope.Dialect().HasIndex(scope.QualifyTableName(scope.TableName()), indexName) {
 		return
 	}
 
@@ -126,13 +201,16 @@ func (scope *Scope) addIndex(unique bool, indexName string, column ...string) {
 		sqlCreate = "CREATE UNIQUE INDEX"
 	}
 
-	scope.Raw(fmt.Sprintf("%s %v ON %v(%v) %v", sqlCreate, indexName, scope.QuotedTableName(), strings.Join(columns, ", "), scope.whereSQL())).Exec()
+	scope.Raw(fmt.Sprintf("%s %v ON %v(%v) %v", sqlCreate, indexName, scope.QuotedQualifiedTableName(), strings.Join(columns, ", "), scope.whereSQL())).Exec()
 }
 
 func (scope *Scope) autoForeignKeys() *DB {
 	ms := scope.modelStruct
 	scope.db.migrator.PostHandler(func(db *DB) (err error) {
 		db = db.ModelStruct(ms)
+		if schema := scope.Schema(); schema != "" {
+			db = db.WithSchema(schema)
+		}
 		scope := db.NewModelScope(ms, ms.Value)
 
 		for _, fk := range ms.ForeignKeys {
@@ -150,16 +228,16 @@ func (scope *Scope) addForeignKey(field string, dest string, onDelete string, on
 	// Compatible with old generated key
 	keyName := scope.Dialect().BuildKeyName(scope.TableName(), field, dest, "foreign")
 
-	if scope.Dialect().HasForeignKey(scope.TableName(), keyName) {
+	if scope.Dialect().HasForeignKey(scope.QualifyTableName(scope.TableName()), keyName) {
 		return
 	}
 	var query = `ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s ON DELETE %s ON UPDATE %s;`
-	scope.Raw(fmt.Sprintf(query, scope.QuotedTableName(), scope.quoteIfPossible(keyName), scope.quoteIfPossible(field), dest, onDelete, onUpdate)).Exec()
+	scope.Raw(fmt.Sprintf(query, scope.QuotedQualifiedTableName(), scope.quoteIfPossible(keyName), scope.quoteIfPossible(field), dest, onDelete, onUpdate)).Exec()
 }
 
 func (scope *Scope) removeForeignKey(field string, dest string) {
 	keyName := scope.Dialect().BuildKeyName(scope.TableName(), field, dest, "foreign")
-	if !scope.Dialect().HasForeignKey(scope.TableName(), keyName) {
+	if !scope.Dialect().HasForeignKey(scope.QualifyTableName(scope.TableName()), keyName) {
 		return
 	}
 	var mysql mysql
@@ -169,16 +247,16 @@ func (scope *Scope) removeForeignKey(field string, dest string) {
 	} else {
 		query = `ALTER TABLE %s DROP CONSTRAINT %s;`
 	}
-	scope.Raw(fmt.Sprintf(query, scope.QuotedTableName(), scope.quoteIfPossible(keyName))).Exec()
+	scope.Raw(fmt.Sprintf(query, scope.QuotedQualifiedTableName(), scope.quoteIfPossible(keyName))).Exec()
 }
 
 func (scope *Scope) removeIndex(indexName string) {
-	scope.Dialect().RemoveIndex(scope.TableName(), indexName)
+	scope.Dialect().RemoveIndex(scope.QualifyTableName(scope.TableName()), indexName)
 }
 
 func (scope *Scope) autoMigrate(parentScope *Scope) *Scope {
-	tableName := scope.TableName()
-	quotedTableName := scope.QuotedTableName()
+	tableName := scope.QualifyTableName(scope.TableName())
+	quotedTableName := scope.QuotedQualifiedTableName()
 	scope.modelStruct.TypeCallbacks.TypeRegistrator.Call("Migrate", Before, scope, parentScope)
 
 	if !scope.Dialect().HasTable(tableName) {
@@ -191,7 +269,12 @@ func (scope *Scope) autoMigrate(parentScope *Scope) *Scope {
 		for _, field := range scope.Struct().Fields {
 			if field.IsNormal && !field.IsReadOnly && field.StructIndex != nil {
 				if !scope.Dialect().HasColumn(tableName, field.DBName) {
-					sqlTag := scope.Dialect().DataTypeOf(field.Structure())
+					// mysql adds STORED generated column by copy of table, sqlite does not add it
+					if field.Generated != nil && field.Generated.Stored && scope.Dialect().GetName() == "sqlite3" {
+						scope.Err(errors.Wrapf(ErrStoredGeneratedColumn, "add column %q to %v", field.DBName, quotedTableName))
+						return scope
+					}
+					sqlTag := scope.columnSQLOf(field)
 					scope.Raw(fmt.Sprintf("ALTER TABLE %v ADD %v %v;", quotedTableName, scope.Quote(field.DBName), sqlTag)).Exec()
 					if scope.HasError() {
 						return scope
@@ -204,6 +287,7 @@ func (scope *Scope) autoMigrate(parentScope *Scope) *Scope {
 			}
 		}
 		scope.autoIndex()
+		scope.autoFullText()
 		scope.autoForeignKeys()
 		if !scope.HasError() {
 			scope.modelStruct.TypeCallbacks.TypeRegistrator.Call("Migrate", After, scope, parentScope)
@@ -227,7 +311,7 @@ func (scope *Scope) createChildrenTables() {
 }
 
 func (scope *Scope) autoIndex() *Scope {
-	tableName := scope.TableName()
+	tableName := scope.QualifyTableName(scope.TableName())
 	for _, ix := range scope.modelStruct.Indexes {
 		name, sql := ix.SqlCreate(scope.db.dialect, tableName)
 		if !scope.Dialect().HasIndex(tableName, name) {
@@ -57,9 +57,9 @@ func (scope *Scope) buildSelectQuery(clause *Clause) (result *Query) {
 
 func (scope *Scope) whereSQL() (sql string) {
 	var (
-		quotedTableName                                = scope.QuotedTableName()
-		deletedAtField, hasDeletedAtField              = scope.Struct().FieldsByName["DeletedAt"]
-		primaryConditions, andConditions, orConditions []string
+		quotedTableName                   = scope.QuotedTableName()
+		deletedAtField, hasDeletedAtField = scope.Struct().FieldsByName["DeletedAt"]
+		primaryConditions                 []string
 	)
 
 	if !scope.Search.Unscoped && hasDeletedAtField {
@@ -67,6 +67,10 @@ func (scope *Scope) whereSQL() (sql string) {
 		primaryConditions = append(primaryConditions, sql)
 	}
 
+	if sql, ok := scope.tenantCondition(scope.Struct(), quotedTableName); ok {
+		primaryConditions = append(primaryConditions, sql)
+	}
+
 	rt := indirectType(reflect.TypeOf(scope.Value))
 	switch rt.Kind() {
 	case reflect.Struct:
@@ -88,7 +92,39 @@ func (scope *Scope) whereSQL() (sql string) {
 		}
 	}
 
-	for _, clause := range scope.Search.whereConditions {
+	if scope.tree != nil && scope.tree.where != nil {
+		if sql, err := scope.tree.where.BuildCondition(scope, true).Build(scope); err != nil {
+			scope.Err(err)
+		} else {
+			primaryConditions = append(primaryConditions, sql)
+		}
+	}
+
+	// the conditions of model scopes are grouped, so they are not affected by the `OR` conditions of search
+	for _, s := range scope.modelScopeSearches {
+		if sql := scope.conditionsSQL(s); sql != "" {
+			primaryConditions = append(primaryConditions, "("+sql+")")
+		}
+	}
+
+	combinedSQL := scope.conditionsSQL(scope.Search)
+
+	if len(primaryConditions) > 0 {
+		sql = "WHERE " + strings.Join(primaryConditions, " AND ")
+		if len(combinedSQL) > 0 {
+			sql = sql + " AND (" + combinedSQL + ")"
+		}
+	} else if len(combinedSQL) > 0 {
+		sql = "WHERE " + combinedSQL
+	}
+	return
+}
+
+// conditionsSQL returns the where, or and not conditions of search combined
+func (scope *Scope) conditionsSQL(s *search) (sql string) {
+	var andConditions, orConditions []string
+
+	for _, clause := range s.whereConditions {
 		if sql, err := clause.BuildCondition(scope, true).Build(scope); err != nil {
 			scope.Err(err)
 		} else if sql != "" {
@@ -96,7 +132,7 @@ func (scope *Scope) whereSQL() (sql string) {
 		}
 	}
 
-	for _, clause := range scope.Search.orConditions {
+	for _, clause := range s.orConditions {
 		if sql, err := clause.BuildCondition(scope, true).Build(scope); err != nil {
 			scope.Err(err)
 		} else if sql != "" {
@@ -104,7 +140,7 @@ func (scope *Scope) whereSQL() (sql string) {
 		}
 	}
 
-	for _, clause := range scope.Search.notConditions {
+	for _, clause := range s.notConditions {
 		if sql, err := clause.BuildCondition(scope, false).Build(scope); err != nil {
 			scope.Err(err)
 		} else if sql != "" {
@@ -113,22 +149,13 @@ func (scope *Scope) whereSQL() (sql string) {
 	}
 
 	orSQL := strings.Join(orConditions, " OR ")
-	combinedSQL := strings.Join(andConditions, " AND ")
-	if len(combinedSQL) > 0 {
+	sql = strings.Join(andConditions, " AND ")
+	if len(sql) > 0 {
 		if len(orSQL) > 0 {
-			combinedSQL = combinedSQL + " OR " + orSQL
+			sql = sql + " OR " + orSQL
 		}
 	} else {
-		combinedSQL = orSQL
-	}
-
-	if len(primaryConditions) > 0 {
-		sql = "WHERE " + strings.Join(primaryConditions, " AND ")
-		if len(combinedSQL) > 0 {
-			sql = sql + " AND (" + combinedSQL + ")"
-		}
-	} else if len(combinedSQL) > 0 {
-		sql = "WHERE " + combinedSQL
+		sql = orSQL
 	}
 	return
 }
@@ -196,7 +223,14 @@ func (scope *Scope) selectSQL() (sql string) {
 }
 
 func (scope *Scope) orderSQL() string {
-	if len(scope.Search.orders) == 0 || scope.Search.ignoreOrderQuery {
+	if scope.Search.ignoreOrderQuery {
+		return ""
+	}
+	if len(scope.Search.orders) == 0 {
+		// tree query results are ordered by depth, unless counting
+		if scope.tree != nil && scope.tree.order != "" && !scope.counter {
+			return " ORDER BY " + scope.tree.order
+		}
 		return ""
 	}
 
@@ -258,6 +292,10 @@ func (scope *Scope) joinsSQL() string {
 		}
 	}
 
+	if scope.tree != nil && scope.tree.join != "" {
+		joinConditions = append(joinConditions, scope.tree.join)
+	}
+
 	return strings.Join(joinConditions, " ") + " "
 }
 
@@ -265,7 +303,9 @@ func (scope *Scope) prepareQuerySQL() {
 	if scope.Search.raw {
 		scope.Raw(scope.CombinedConditionSql())
 	} else {
-		scope.Raw(fmt.Sprintf("SELECT %v FROM %v %v", scope.selectSQL(), scope.fromSql(), scope.CombinedConditionSql()))
+		// CTE must be built first to keep order of bind vars
+		with := scope.withSQL()
+		scope.Raw(fmt.Sprintf("%vSELECT %v FROM %v %v", with, scope.selectSQL(), scope.fromSql(), scope.CombinedConditionSql()))
 	}
 	return
 }
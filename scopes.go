@@ -0,0 +1,155 @@
+package aorm
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	OptKeyScopes         = "aorm:scopes"
+	OptKeyUnscopedScopes = "aorm:unscoped_scopes"
+)
+
+// ModelScope is the named scope of model. The default scopes are applied to every query, update and delete of
+// model, like the soft delete condition.
+type ModelScope struct {
+	Name    string
+	Func    func(db *DB) *DB
+	Default bool
+}
+
+// RegisterScope registers the named scope of model, used by `DB.Scope`
+//
+//	aorm.RegisterScope(&Order{}, "paid", func(db *aorm.DB) *aorm.DB {
+//		return db.Where("paid_at IS NOT NULL")
+//	})
+//	db.Scope("paid").Find(&orders)
+func RegisterScope(model interface{}, name string, f func(db *DB) *DB) {
+	StructOf(model).RegisterScope(name, f)
+}
+
+// RegisterDefaultScope registers the named scope of model applied to every query, update and delete of model,
+// unless disabled by `DB.Unscoped`
+func RegisterDefaultScope(model interface{}, name string, f func(db *DB) *DB) {
+	StructOf(model).RegisterDefaultScope(name, f)
+}
+
+// RegisterScope registers the named scope, see RegisterScope
+func (this *ModelStruct) RegisterScope(name string, f func(db *DB) *DB) {
+	this.registerScope(&ModelScope{Name: name, Func: f})
+}
+
+// RegisterDefaultScope registers the default named scope, see RegisterDefaultScope
+func (this *ModelStruct) RegisterDefaultScope(name string, f func(db *DB) *DB) {
+	this.registerScope(&ModelScope{Name: name, Func: f, Default: true})
+}
+
+func (this *ModelStruct) registerScope(s *ModelScope) {
+	if s.Name == "" || s.Func == nil {
+		panic(errors.Errorf("aorm: scope of %s must have name and func", this.Fqn()))
+	}
+	if this.Scopes == nil {
+		this.Scopes = map[string]*ModelScope{}
+	}
+	if old, ok := this.Scopes[s.Name]; ok && old.Default {
+		for i, ds := range this.DefaultScopes {
+			if ds == old {
+				this.DefaultScopes = append(this.DefaultScopes[:i:i], this.DefaultScopes[i+1:]...)
+				break
+			}
+		}
+	}
+	this.Scopes[s.Name] = s
+	if s.Default {
+		this.DefaultScopes = append(this.DefaultScopes, s)
+	}
+}
+
+// Scope applies the named scopes of model, registered by RegisterScope
+func (s *DB) Scope(names ...string) *DB {
+	return s.Set(OptKeyScopes, appendScopeNames(s, OptKeyScopes, names))
+}
+
+func appendScopeNames(db *DB, key string, names []string) (result []string) {
+	if v, ok := db.Get(key); ok && v != nil {
+		result = append(result, v.([]string)...)
+	}
+	return append(result, names...)
+}
+
+// applyModelScopes applies the named and default scopes of model. The conditions of each scope are ANDed as
+// group with the conditions of search, and its joins, having conditions and orders are merged into search.
+func (scope *Scope) applyModelScopes() {
+	if scope.modelScopesApplied || scope.Search.raw || scope.Value == nil {
+		return
+	}
+	scope.modelScopesApplied = true
+
+	var names []string
+	if v, ok := scope.Get(OptKeyScopes); ok && v != nil {
+		names = v.([]string)
+	}
+	ms := scope.Struct()
+	if len(names) == 0 && (len(ms.DefaultScopes) == 0 || scope.Search.Unscoped) {
+		return
+	}
+
+	var (
+		scopes  []*ModelScope
+		applied = map[string]bool{}
+	)
+	if !scope.Search.Unscoped {
+		var unscoped = map[string]bool{}
+		if v, ok := scope.Get(OptKeyUnscopedScopes); ok && v != nil {
+			for _, name := range v.([]string) {
+				unscoped[name] = true
+			}
+		}
+		for _, s := range ms.DefaultScopes {
+			if !unscoped[s.Name] {
+				applied[s.Name] = true
+				scopes = append(scopes, s)
+			}
+		}
+	}
+	// the named scopes are applied even if unscoped
+	for _, name := range names {
+		s, ok := ms.Scopes[name]
+		if !ok {
+			scope.Err(errors.Errorf("aorm: scope %q of %s does not exists", name, ms.Fqn()))
+			continue
+		}
+		if !applied[name] {
+			applied[name] = true
+			scopes = append(scopes, s)
+		}
+	}
+
+	for _, s := range scopes {
+		db := s.Func(scope.db.New())
+		if db == nil {
+			continue
+		}
+		if db.Error != nil {
+			scope.Err(errors.Wrapf(db.Error, "aorm: scope %q of %s", s.Name, ms.Fqn()))
+			continue
+		}
+		if db.search == nil {
+			continue
+		}
+		search := db.search
+		if search.selects != nil || search.group != "" || isLimitSet(search.limit) || isLimitSet(search.offset) {
+			scope.Err(errors.Errorf("aorm: scope %q of %s: select, group, limit and offset are not supported", s.Name, ms.Fqn()))
+			continue
+		}
+		if len(search.whereConditions) > 0 || len(search.orConditions) > 0 || len(search.notConditions) > 0 {
+			scope.modelScopeSearches = append(scope.modelScopeSearches, search)
+		}
+		scope.Search.joinConditions = append(scope.Search.joinConditions, search.joinConditions...)
+		scope.Search.havingConditions = append(scope.Search.havingConditions, search.havingConditions...)
+		scope.Search.orders = append(scope.Search.orders, search.orders...)
+	}
+}
+
+func isLimitSet(v interface{}) bool {
+	return v != nil && v != -1
+}
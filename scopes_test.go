@@ -0,0 +1,83 @@
+package aorm_test
+
+import (
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type ScopedOrder struct {
+	aorm.Model
+	Code     string
+	Paid     bool
+	Archived bool `sql:"not null;default:false"`
+}
+
+func init() {
+	aorm.RegisterDefaultScope(&ScopedOrder{}, "active", func(db *aorm.DB) *aorm.DB {
+		return db.Where("archived = ?", false)
+	})
+	aorm.RegisterScope(&ScopedOrder{}, "paid", func(db *aorm.DB) *aorm.DB {
+		return db.Where("paid = ?", true)
+	})
+	aorm.RegisterScope(&ScopedOrder{}, "first_codes", func(db *aorm.DB) *aorm.DB {
+		return db.Where("code = ?", "o1").Or("code = ?", "o2")
+	})
+	aorm.RegisterScope(&ScopedOrder{}, "limited", func(db *aorm.DB) *aorm.DB {
+		return db.Limit(1)
+	})
+}
+
+func TestModelScopes(t *testing.T) {
+	DB.DropTableIfExists(&ScopedOrder{})
+	DB.AutoMigrate(&ScopedOrder{})
+
+	for _, order := range []ScopedOrder{
+		{Code: "o1", Paid: true},
+		{Code: "o2"},
+		{Code: "o3", Paid: true, Archived: true},
+	} {
+		DB.Save(&order)
+	}
+
+	var count int
+	if DB.Model(&ScopedOrder{}).Count(&count); count != 2 {
+		t.Errorf("default scope should exclude archived orders, but counted %v", count)
+	}
+
+	var orders []ScopedOrder
+	DB.Scope("paid").Find(&orders)
+	if len(orders) != 1 || orders[0].Code != "o1" {
+		t.Errorf("named scope should find paid orders not archived, but got %v", orders)
+	}
+
+	orders = nil
+	DB.Unscoped("active").Scope("paid").Order("code").Find(&orders)
+	if len(orders) != 2 || orders[0].Code != "o1" || orders[1].Code != "o3" {
+		t.Errorf("unscoped default scope should find archived orders, but got %v", orders)
+	}
+
+	orders = nil
+	DB.Where("code = ?", "o1").Or("code = ?", "o3").Order("code").Find(&orders)
+	if len(orders) != 1 || orders[0].Code != "o1" {
+		t.Errorf("or conditions should not bypass default scope, but got %v", orders)
+	}
+
+	orders = nil
+	DB.Scope("first_codes").Where("paid = ?", true).Find(&orders)
+	if len(orders) != 1 || orders[0].Code != "o1" {
+		t.Errorf("or conditions of scope should be grouped, but got %v", orders)
+	}
+
+	if err := DB.Scope("limited").Find(&orders).Error; err == nil {
+		t.Errorf("scope with limit should return error")
+	}
+
+	if n := DB.Opt(aorm.OptForceSingleUpdate()).Model(&ScopedOrder{}).Update("code", "x").RowsAffected; n != 2 {
+		t.Errorf("default scope should apply to updates, but updated %v", n)
+	}
+
+	if err := DB.Scope("unknown").Find(&orders).Error; err == nil {
+		t.Errorf("unknown scope should return error")
+	}
+}
@@ -33,6 +33,9 @@ type search struct {
 	defaultColumnValue     func(scope *Scope, record interface{}, column string) interface{}
 	columnsScannerCallback func(scope *Scope, record interface{}, columns []string, values []interface{})
 	ignorePrimaryFields    bool
+	treeQuery              *treeQuery
+	ctes                   []*searchCTE
+	fromQuery              *Query
 }
 
 type searchPreload struct {
@@ -225,6 +228,28 @@ func (s *search) Table(name string) *search {
 	return s
 }
 
+func (s *search) tree(query *treeQuery) *search {
+	s.treeQuery = query
+	return s
+}
+
+func (s *search) With(cte *searchCTE) *search {
+	var ctes []*searchCTE
+	for _, c := range s.ctes {
+		if c.name != cte.name {
+			ctes = append(ctes, c)
+		}
+	}
+	s.ctes = append(ctes, cte)
+	return s
+}
+
+func (s *search) FromQuery(query *Query, alias string) *search {
+	s.fromQuery = query
+	s.tableAlias = alias
+	return s
+}
+
 func (s *search) From(from string) *search {
 	s.from = from
 	return s
@@ -63,13 +63,15 @@ func (this *StructIndex) String() (s string) {
 	return strings.TrimSpace(s)
 }
 
+// SqlCreate returns the index name and create sql. The table name may be qualified with schema.
 func (this *StructIndex) SqlCreate(d Dialector, tableName string) (name, sql string) {
-	name = this.BuildName(d, tableName)
+	_, name = SplitSchemaTableName(tableName)
+	name = this.BuildName(d, name)
 	sql = "CREATE "
 	if this.Unique {
 		sql += "UNIQUE "
 	}
-	sql += "INDEX " + Quote(d, name) + " ON " + Quote(d, tableName) + "("
+	sql += "INDEX " + Quote(d, name) + " ON " + QuotePath(d, tableName) + "("
 	columns := make([]string, len(this.Fields))
 	for i, f := range this.Fields {
 		columns[i] = Quote(d, f.DBName)
@@ -86,7 +88,11 @@ func (this *StructIndex) SqlDrop(d interface {
 	Quoter
 	KeyNamer
 }, tableName string) (name, sql string) {
+	schema, tableName := SplitSchemaTableName(tableName)
 	name = this.BuildName(d, tableName)
+	if schema != "" {
+		return name, "DROP INDEX " + Quote(d, schema) + "." + Quote(d, name)
+	}
 	return name, "DROP INDEX " + Quote(d, name)
 }
 
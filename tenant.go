@@ -0,0 +1,114 @@
+package aorm
+
+import (
+	"fmt"
+
+	"github.com/moisespsena-go/bid"
+)
+
+const (
+	TenantFieldTenantID  = "TenantID"
+	TenantColumnTenantID = "tenant_id"
+
+	OptKeyTenant         = "aorm:tenant"
+	OptKeyTenantUnscoped = "aorm:tenant_unscoped"
+)
+
+// Tenanted is an embeddable for models of shared-schema multi-tenancy. When the db has tenant (see
+// `DB.SetTenant`), queries, updates and deletes are constrained by tenant column and creates stamp it.
+type Tenanted struct {
+	TenantID bid.BID `sql:"index"`
+}
+
+// TenantField returns the tenant field of model, or nil if model is not tenanted
+func (this *ModelStruct) TenantField() *StructField {
+	if field, ok := this.FieldsByName[TenantFieldTenantID]; ok && field.IsNormal {
+		return field
+	}
+	return nil
+}
+
+// SetTenant set the current tenant used to constrain and stamp tenanted models
+func (s *DB) SetTenant(tenant ID) *DB {
+	return s.Set(OptKeyTenant, tenant)
+}
+
+// GetTenant returns the current tenant
+func (s *DB) GetTenant() (tenant ID, ok bool) {
+	if v, ok := s.Get(OptKeyTenant); ok && v != nil {
+		tenant = v.(ID)
+		return tenant, tenant != nil && !tenant.IsZero()
+	}
+	return
+}
+
+// UnscopedTenant disables the tenant constraints, but keep tenant stamping on creates
+func (s *DB) UnscopedTenant() *DB {
+	return s.Set(OptKeyTenantUnscoped, true)
+}
+
+// tenantOf returns the tenant column and value to constrain model if model is tenanted, db has tenant and
+// tenant is not unscoped
+func (scope *Scope) tenantOf(model *ModelStruct) (column string, tenant interface{}, ok bool) {
+	field := model.TenantField()
+	if field == nil || scope.db.GetBool(OptKeyTenantUnscoped) {
+		return
+	}
+	if id, ok := scope.db.GetTenant(); ok {
+		return scope.Quote(field.DBName), RawOfId(id), true
+	}
+	return
+}
+
+// tenantCondition returns the tenant condition of table, see tenantOf
+func (scope *Scope) tenantCondition(model *ModelStruct, quotedTableName string) (cond string, ok bool) {
+	if column, tenant, ok := scope.tenantOf(model); ok {
+		return fmt.Sprintf("%v.%v = %v", quotedTableName, column, scope.AddToVars(tenant)), true
+	}
+	return
+}
+
+// tenantForCreateCallback will set `TenantID` when creating
+func tenantForCreateCallback(scope *Scope) {
+	if field, ok := scope.FieldByName(TenantFieldTenantID); ok && field.IsBlank {
+		if tenant, ok := scope.db.GetTenant(); ok {
+			scope.Err(scope.SetColumn(TenantFieldTenantID, RawOfId(tenant)))
+		}
+	}
+}
+
+// tenantForUpdateCallback will set blank `TenantID` when saving struct
+func tenantForUpdateCallback(scope *Scope) {
+	if _, ok := scope.InstanceGet("aorm:update_attrs"); !ok {
+		tenantForCreateCallback(scope)
+	}
+}
+
+// joinTableTenantField returns the tenant field of join table, if source or destination model is tenanted
+func joinTableTenantField(handler JoinTableHandlerInterface) *StructField {
+	if field := handler.Source().ModelStruct.TenantField(); field != nil {
+		return field
+	}
+	return handler.Destination().ModelStruct.TenantField()
+}
+
+// scopeToTenant prepends the tenant field to unique indexes, so uniqueness is per tenant. The column level
+// UNIQUE constraint of index fields is removed, because it is global.
+func (this IndexMap) scopeToTenant(tenantField *StructField) {
+Indexes:
+	for _, ix := range this {
+		for _, f := range ix.Fields {
+			if f == tenantField {
+				continue Indexes
+			}
+		}
+		for _, f := range ix.Fields {
+			if _, ok := f.TagSettings["UNIQUE"]; ok {
+				// the settings may be shared with the field of other model, e.g. of embedded struct
+				f.TagSettings = f.TagSettings.Clone()
+				delete(f.TagSettings, "UNIQUE")
+			}
+		}
+		ix.Fields = append([]*StructField{tenantField}, ix.Fields...)
+	}
+}
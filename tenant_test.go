@@ -0,0 +1,100 @@
+package aorm_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type TenantAccount struct {
+	aorm.Model
+	Name string
+}
+
+type TenantProject struct {
+	aorm.Model
+	aorm.Tenanted
+	Code string `sql:"unique"`
+}
+
+type TenantCoded struct {
+	Number string `sql:"unique"`
+}
+
+type TenantInvoice struct {
+	aorm.Model
+	aorm.Tenanted
+	TenantCoded
+}
+
+type GlobalInvoice struct {
+	aorm.Model
+	TenantCoded
+}
+
+func TestTenanted(t *testing.T) {
+	DB.DropTableIfExists(&TenantAccount{}, &TenantProject{})
+	DB.AutoMigrate(&TenantAccount{}, &TenantProject{})
+
+	acme, globex := TenantAccount{Name: "acme"}, TenantAccount{Name: "globex"}
+	DB.Save(&acme)
+	DB.Save(&globex)
+
+	var (
+		acmeDB   = DB.SetTenant(aorm.IDOf(&acme))
+		globexDB = DB.SetTenant(aorm.IDOf(&globex))
+	)
+
+	acmeProject := TenantProject{Code: "p1"}
+	if err := acmeDB.Save(&acmeProject).Error; err != nil {
+		t.Fatalf("no error should happen when create tenanted record, but got %v", err)
+	}
+	if !bytes.Equal(acmeProject.TenantID, acme.ID) {
+		t.Errorf("tenant should be stamped on create, but got %v", acmeProject.TenantID)
+	}
+
+	globexProject := TenantProject{Code: "p1"}
+	if err := globexDB.Save(&globexProject).Error; err != nil {
+		t.Errorf("unique index should be per tenant, but got %v", err)
+	}
+
+	var projects []TenantProject
+	acmeDB.Find(&projects)
+	if len(projects) != 1 || !bytes.Equal(projects[0].ID, acmeProject.ID) {
+		t.Errorf("should find only the projects of tenant, but got %v", projects)
+	}
+
+	if err := globexDB.First(&TenantProject{}, "id = ?", acmeProject.ID).Error; !aorm.IsRecordNotFoundError(err) {
+		t.Errorf("should not find project of other tenant, but got %v", err)
+	}
+
+	if n := globexDB.Opt(aorm.OptForceSingleUpdate()).Model(&TenantProject{}).Where("id = ?", acmeProject.ID).Update("code", "p2").RowsAffected; n != 0 {
+		t.Errorf("should not update project of other tenant, but updated %v", n)
+	}
+
+	if n := globexDB.Where("id = ?", acmeProject.ID).Delete(&TenantProject{}).RowsAffected; n != 0 {
+		t.Errorf("should not delete project of other tenant, but deleted %v", n)
+	}
+
+	var count int
+	if globexDB.UnscopedTenant().Model(&TenantProject{}).Count(&count); count != 2 {
+		t.Errorf("unscoped tenant should count projects of all tenants, but got %v", count)
+	}
+}
+
+func TestTenantedSharedUniqueField(t *testing.T) {
+	if len(DB.StructOf(&TenantInvoice{}).UniqueIndexes) != 1 || len(DB.StructOf(&GlobalInvoice{}).UniqueIndexes) != 1 {
+		t.Fatalf("models should have an unique index")
+	}
+	for _, ix := range DB.StructOf(&TenantInvoice{}).UniqueIndexes {
+		if len(ix.Fields) != 2 || ix.Fields[0].Name != aorm.TenantFieldTenantID {
+			t.Errorf("unique index of tenanted model should be scoped to tenant, but got %v", ix.Fields)
+		}
+	}
+	for _, ix := range DB.StructOf(&GlobalInvoice{}).UniqueIndexes {
+		if len(ix.Fields) != 1 || ix.Fields[0].Name != "Number" {
+			t.Errorf("unique index of field shared with non tenanted model should be kept, but got %v", ix.Fields)
+		}
+	}
+}
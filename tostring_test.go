@@ -0,0 +1,31 @@
+package aorm
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/bid"
+)
+
+func TestToString(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{"int", 1, "1"},
+		{"reflect int", reflect.ValueOf(1), "1"},
+		{"reflect int pointer", reflect.ValueOf(&[]int{2}[0]), "2"},
+		{"reflect bytes", reflect.ValueOf([]byte("ab")), "ab"},
+		{"bytes", []byte("ab"), "ab"},
+		{"bid", bid.BID("ab"), "ab"},
+		{"values", []interface{}{1, "a"}, "1_a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toString(tt.value); got != tt.want {
+				t.Errorf("toString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
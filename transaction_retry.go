@@ -0,0 +1,144 @@
+package aorm
+
+import (
+	"context"
+	"database/sql"
+	"math/rand"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	DefaultTransactionMaxAttempts = 5
+	DefaultTransactionMinBackoff  = 10 * time.Millisecond
+	DefaultTransactionMaxBackoff  = time.Second
+)
+
+// TransactionRetryOptions configures the TransactionRetry
+type TransactionRetryOptions struct {
+	// MaxAttempts is the maximum number of attempts, including the first. Defaults to 5.
+	MaxAttempts int
+	// MinBackoff is the delay before the first retry, doubled on each retry. Defaults to 10ms.
+	MinBackoff time.Duration
+	// MaxBackoff limits the delay between retries. Defaults to 1s.
+	MaxBackoff time.Duration
+	// Isolation is the isolation level of transactions, e.g. sql.LevelSerializable
+	Isolation sql.IsolationLevel
+	ReadOnly  bool
+	// OnRetry is called before each retry with the failed attempt number and its error
+	OnRetry func(attempt int, err error)
+}
+
+func (this TransactionRetryOptions) backoff(attempt int) time.Duration {
+	d := this.MinBackoff
+	for i := 1; i < attempt && d < this.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > this.MaxBackoff {
+		d = this.MaxBackoff
+	}
+	// jitter into [d/2, d)
+	half := int64(d / 2)
+	if half <= 0 {
+		return d
+	}
+	return time.Duration(half + rand.Int63n(half))
+}
+
+// TransactionRetry execute func `f` into transaction, running it again, after a backoff delay with jitter, while
+// the transaction fails by serialization failure or deadlock, as classified by Dialector.IsRetryableError. Func
+// `f` must not have side effects out of database, because it may be executed many times.
+//
+//	err := db.TransactionRetry(func(tx *aorm.DB) error {
+//		return tx.Model(&account).Update("balance", aorm.Expr("balance - ?", 10)).Error
+//	}, aorm.TransactionRetryOptions{Isolation: sql.LevelSerializable})
+func (s *DB) TransactionRetry(f func(db *DB) (err error), opts ...TransactionRetryOptions) (err error) {
+	var opt TransactionRetryOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.MaxAttempts <= 0 {
+		opt.MaxAttempts = DefaultTransactionMaxAttempts
+	}
+	if opt.MinBackoff <= 0 {
+		opt.MinBackoff = DefaultTransactionMinBackoff
+	}
+	if opt.MaxBackoff <= 0 {
+		opt.MaxBackoff = DefaultTransactionMaxBackoff
+	}
+
+	var txOpts *sql.TxOptions
+	if opt.Isolation != sql.LevelDefault || opt.ReadOnly {
+		txOpts = &sql.TxOptions{Isolation: opt.Isolation, ReadOnly: opt.ReadOnly}
+	}
+
+	ctx := s.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err = s.transaction(txOpts, f); err == nil || attempt >= opt.MaxAttempts || !s.IsRetryableError(err) {
+			return
+		}
+		if opt.OnRetry != nil {
+			opt.OnRetry(attempt, err)
+		}
+		timer := time.NewTimer(opt.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Wrap(ctx.Err(), "transaction retry")
+		case <-timer.C:
+		}
+	}
+}
+
+// IsRetryableError returns if err, or any error wrapped by it, is a serialization failure or deadlock for the
+// dialect of db
+func (s *DB) IsRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	for _, err := range unwrapErrors(err) {
+		if s.dialect.IsRetryableError(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// SQLStateOf returns the SQLSTATE code of err, or any error wrapped by it, if the driver error provides it
+func SQLStateOf(err error) string {
+	for _, err := range unwrapErrors(err) {
+		if e, ok := err.(interface{ SQLState() string }); ok {
+			return e.SQLState()
+		}
+	}
+	return ""
+}
+
+// unwrapErrors returns err and the chain of errors wrapped by it, including the items of Errors
+func unwrapErrors(err error) (result []error) {
+	for err != nil {
+		result = append(result, err)
+		var next error
+		switch e := err.(type) {
+		case Errors:
+			for _, err := range e {
+				result = append(result, unwrapErrors(err)...)
+			}
+			return
+		case interface{ Unwrap() error }:
+			next = e.Unwrap()
+		case interface{ Cause() error }:
+			next = e.Cause()
+		}
+		if next == err {
+			return
+		}
+		err = next
+	}
+	return
+}
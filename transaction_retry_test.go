@@ -0,0 +1,65 @@
+package aorm_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/moisespsena-go/aorm"
+	"github.com/moisespsena-go/aorm/aormtest"
+)
+
+func TestTransactionRetry(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+	mock.ExpectRegexp(`^UPDATE accounts`).WillReturnError(errors.New("database is locked"))
+	mock.ExpectRegexp(`^UPDATE accounts`).WillReturnResult(0, 1)
+
+	var attempts, retries int
+	err := db.TransactionRetry(func(tx *aorm.DB) error {
+		attempts++
+		return tx.Exec("UPDATE accounts SET balance = balance - 1").Error
+	}, aorm.TransactionRetryOptions{
+		Isolation:  sql.LevelSerializable,
+		MinBackoff: time.Millisecond,
+		OnRetry: func(attempt int, err error) {
+			retries++
+		},
+	})
+	if err != nil {
+		t.Fatalf("no error should happen when retry transaction, but got %v", err)
+	}
+	if attempts != 2 || retries != 1 {
+		t.Errorf("transaction should be retried once, but got %d attempts and %d retries", attempts, retries)
+	}
+
+	var begins, rollbacks int
+	for _, stmt := range mock.Statements() {
+		switch stmt.SQL {
+		case "BEGIN ISOLATION LEVEL SERIALIZABLE":
+			begins++
+		case "ROLLBACK":
+			rollbacks++
+		}
+	}
+	if begins != 2 || rollbacks != 1 {
+		t.Errorf("each attempt should run into serializable transaction, but got %v", mock.Statements())
+	}
+}
+
+func TestTransactionRetryNotRetryable(t *testing.T) {
+	db, mock := aormtest.New(t, "sqlite3")
+	mock.ExpectRegexp(`^UPDATE accounts`).WillReturnError(errors.New("constraint failed"))
+
+	var attempts int
+	err := db.TransactionRetry(func(tx *aorm.DB) error {
+		attempts++
+		return tx.Exec("UPDATE accounts SET balance = balance - 1").Error
+	})
+	if err == nil || attempts != 1 {
+		t.Errorf("not retryable error should be returned without retry, but got %v after %d attempts", err, attempts)
+	}
+	if db.IsRetryableError(err) {
+		t.Errorf("%v should not be retryable", err)
+	}
+}
@@ -0,0 +1,155 @@
+package aorm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TreeCTEName is the name of recursive common table expression used by tree queries
+	TreeCTEName = "aorm_tree"
+	// TreeDepthColumn is the depth column of tree common table expression. The depth of direct
+	// children or parent of node is 1.
+	TreeDepthColumn = "aorm_tree_depth"
+)
+
+// TreeMaxDepth is the depth limit of tree queries without depth, that stops the recursion over cyclic parent
+// references
+var TreeMaxDepth = 100
+
+type treeQueryKind uint8
+
+const (
+	treeDescendants treeQueryKind = iota + 1
+	treeAncestors
+	treeRoots
+)
+
+type treeQuery struct {
+	kind  treeQueryKind
+	node  interface{}
+	depth int
+}
+
+// scopeTree is the tree query parts of scope, built with the CTE on each query preparation
+type scopeTree struct {
+	join  string
+	where *Clause
+	order string
+}
+
+// TreeParentField returns the self-referencing belongs-to field of model struct, used by tree queries
+func (this *ModelStruct) TreeParentField() *StructField {
+	for _, field := range this.RelatedFields {
+		if rel := field.Relationship; rel != nil && rel.Kind == "belongs_to" && len(rel.ForeignDBNames) == 1 &&
+			indirectType(field.Struct.Type) == this.Type {
+			return field
+		}
+	}
+	return nil
+}
+
+// Descendants find descendants of node, from self-referencing belongs-to models, until depth. If depth <= 0,
+// finds all descendants until TreeMaxDepth. Results are ordered by depth when no order is specified.
+//
+//	db.Descendants(&category, 2).Find(&categories)
+func (s *DB) Descendants(node interface{}, depth int) *DB {
+	return s.clone().search.tree(&treeQuery{kind: treeDescendants, node: node, depth: depth}).db
+}
+
+// Ancestors find ancestors of node, from self-referencing belongs-to models, until TreeMaxDepth. Results are
+// ordered by depth, closest parent first, when no order is specified.
+//
+//	db.Ancestors(&category).Find(&categories)
+func (s *DB) Ancestors(node interface{}) *DB {
+	return s.clone().search.tree(&treeQuery{kind: treeAncestors, node: node}).db
+}
+
+// Roots find records without parent, from self-referencing belongs-to models.
+//
+//	db.Roots().Find(&categories)
+func (s *DB) Roots() *DB {
+	return s.clone().search.tree(&treeQuery{kind: treeRoots}).db
+}
+
+// treeCTE prepares the tree query parts of scope and returns the recursive CTE
+func (scope *Scope) treeCTE() (cte string) {
+	scope.tree = nil
+	tree := scope.Search.treeQuery
+	if tree == nil {
+		return
+	}
+
+	parentField := scope.Struct().TreeParentField()
+	if parentField == nil {
+		scope.Err(errors.Errorf("tree query: %s does not have a self-referencing belongs-to field", scope.Struct().Type))
+		return
+	}
+	if len(scope.Struct().PrimaryFields) != 1 {
+		scope.Err(errors.Errorf("tree query: %s must have one primary field", scope.Struct().Type))
+		return
+	}
+
+	var (
+		rel             = parentField.Relationship
+		fkField         = scope.Struct().FieldsByName[rel.ForeignFieldNames[0]]
+		pk              = scope.Quote(scope.Struct().PrimaryFields[0].DBName)
+		fk              = scope.Quote(rel.ForeignDBNames[0])
+		table           = scope.Quote(scope.QualifyTableName(scope.RealTableName()))
+		quotedTableName = scope.QuotedTableName()
+		cteName         = scope.Quote(TreeCTEName)
+		depth           = scope.Quote(TreeDepthColumn)
+	)
+
+	if tree.kind == treeRoots {
+		if fkField.Struct.Type.Kind() == reflect.Ptr {
+			scope.tree = &scopeTree{where: &Clause{Query: fmt.Sprintf("%v.%v IS NULL", quotedTableName, fk)}}
+		} else {
+			scope.tree = &scopeTree{where: &Clause{fmt.Sprintf("(%v.%v IS NULL OR %v.%v = ?)", quotedTableName, fk, quotedTableName, fk),
+				[]interface{}{reflect.Zero(fkField.Struct.Type).Interface()}}}
+		}
+		return
+	}
+
+	var (
+		instance = InstanceOf(tree.node)
+		anchor   string
+		next     string
+	)
+
+	switch tree.kind {
+	case treeDescendants:
+		anchor = fmt.Sprintf("SELECT %v, %v, 1 FROM %v WHERE %v = %v", pk, fk, table, fk,
+			scope.AddToVars(instance.FieldsMap[scope.Struct().PrimaryFields[0].Name].Field.Interface()))
+		next = fmt.Sprintf("SELECT t.%v, t.%v, %v.%v + 1 FROM %v t INNER JOIN %v ON t.%v = %v.%v",
+			pk, fk, cteName, depth, table, cteName, fk, cteName, pk)
+	case treeAncestors:
+		parentID := instance.FieldsMap[fkField.Name].Field
+		if parentID.Kind() == reflect.Ptr {
+			if parentID.IsNil() {
+				scope.tree = &scopeTree{where: &Clause{Query: "1 <> 1"}}
+				return
+			}
+			parentID = parentID.Elem()
+		}
+		anchor = fmt.Sprintf("SELECT %v, %v, 1 FROM %v WHERE %v = %v", pk, fk, table, pk, scope.AddToVars(parentID.Interface()))
+		next = fmt.Sprintf("SELECT t.%v, t.%v, %v.%v + 1 FROM %v t INNER JOIN %v ON t.%v = %v.%v",
+			pk, fk, cteName, depth, table, cteName, pk, cteName, fk)
+	}
+
+	maxDepth := tree.depth
+	if maxDepth <= 0 {
+		maxDepth = TreeMaxDepth
+	}
+	next += fmt.Sprintf(" WHERE %v.%v < %d", cteName, depth, maxDepth)
+
+	scope.tree = &scopeTree{
+		join:  fmt.Sprintf("INNER JOIN %v ON %v.%v = %v.%v", cteName, cteName, pk, quotedTableName, pk),
+		order: cteName + "." + depth,
+	}
+
+	return fmt.Sprintf("%v (%v) AS (%v UNION ALL %v)", cteName, strings.Join([]string{pk, fk, depth}, ", "), anchor, next)
+}
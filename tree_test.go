@@ -0,0 +1,112 @@
+package aorm_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type TreeNode struct {
+	ID       int
+	Name     string
+	ParentID *int
+	Parent   *TreeNode
+}
+
+func treeNodeNames(nodes []TreeNode) (names []string) {
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	return
+}
+
+func TestTreeQueries(t *testing.T) {
+	DB.DropTableIfExists(&TreeNode{})
+	DB.AutoMigrate(&TreeNode{})
+
+	root := TreeNode{Name: "root"}
+	DB.Save(&root)
+	child := TreeNode{Name: "child", ParentID: &root.ID}
+	DB.Save(&child)
+	grandchild := TreeNode{Name: "grandchild", ParentID: &child.ID}
+	DB.Save(&grandchild)
+	other := TreeNode{Name: "other"}
+	DB.Save(&other)
+
+	var nodes []TreeNode
+	if err := DB.Descendants(&root, 0).Find(&nodes).Error; err != nil {
+		t.Fatalf("no error should happen when find descendants, but got %v", err)
+	}
+	if names := treeNodeNames(nodes); !reflect.DeepEqual(names, []string{"child", "grandchild"}) {
+		t.Errorf("descendants should be child and grandchild, but got %v", names)
+	}
+
+	nodes = nil
+	DB.Descendants(&root, 1).Find(&nodes)
+	if names := treeNodeNames(nodes); !reflect.DeepEqual(names, []string{"child"}) {
+		t.Errorf("descendants with depth 1 should be child, but got %v", names)
+	}
+
+	nodes = nil
+	DB.Ancestors(&grandchild).Find(&nodes)
+	if names := treeNodeNames(nodes); !reflect.DeepEqual(names, []string{"child", "root"}) {
+		t.Errorf("ancestors should be child and root, but got %v", names)
+	}
+
+	nodes = nil
+	DB.Roots().Order("id").Find(&nodes)
+	if names := treeNodeNames(nodes); !reflect.DeepEqual(names, []string{"root", "other"}) {
+		t.Errorf("roots should be root and other, but got %v", names)
+	}
+
+	var count int
+	if DB.Model(&TreeNode{}).Descendants(&root, 0).Count(&count); count != 2 {
+		t.Errorf("descendants count should be 2, but got %v", count)
+	}
+
+	nodes, count = nil, 0
+	descendants := DB.Model(&TreeNode{}).Descendants(&root, 0)
+	descendants.Count(&count)
+	if err := descendants.Find(&nodes).Error; err != nil {
+		t.Fatalf("no error should happen when find descendants after count, but got %v", err)
+	}
+	if names := treeNodeNames(nodes); count != 2 || !reflect.DeepEqual(names, []string{"child", "grandchild"}) {
+		t.Errorf("descendants should be found after count, but got %v, %v", count, names)
+	}
+
+	nodes = nil
+	roots := DB.Roots()
+	roots.Order("id").Find(&nodes)
+	if err := roots.Order("id").Find(&nodes).Error; err != nil || len(nodes) != 2 {
+		t.Errorf("roots should be found again with the same search, but got %v, %v", treeNodeNames(nodes), err)
+	}
+}
+
+func TestTreeQueriesCycle(t *testing.T) {
+	DB.DropTableIfExists(&TreeNode{})
+	DB.AutoMigrate(&TreeNode{})
+
+	a, b := TreeNode{Name: "a"}, TreeNode{Name: "b"}
+	DB.Save(&a)
+	b.ParentID = &a.ID
+	DB.Save(&b)
+	a.ParentID = &b.ID
+	DB.Save(&a)
+
+	defer func(depth int) { aorm.TreeMaxDepth = depth }(aorm.TreeMaxDepth)
+	aorm.TreeMaxDepth = 4
+
+	var nodes []TreeNode
+	if err := DB.Descendants(&a, 0).Find(&nodes).Error; err != nil {
+		t.Fatalf("no error should happen when find descendants of cyclic tree, but got %v", err)
+	}
+	if names := treeNodeNames(nodes); !reflect.DeepEqual(names, []string{"b", "a", "b", "a"}) {
+		t.Errorf("descendants of cyclic tree should stop at max depth, but got %v", names)
+	}
+
+	nodes = nil
+	if err := DB.Ancestors(&a).Find(&nodes).Error; err != nil || len(nodes) != 4 {
+		t.Errorf("ancestors of cyclic tree should stop at max depth, but got %v, %v", treeNodeNames(nodes), err)
+	}
+}
@@ -0,0 +1,188 @@
+package aorm
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const optKeyTxRecords = "aorm:tx_records"
+
+// ChangeEvent is the change of record published after the transaction that changed it completes
+type ChangeEvent struct {
+	// Operation is one of OpCreate, OpUpdate or OpDelete
+	Operation Operation
+	// Committed is false if the transaction was rolled back
+	Committed bool
+	Model     *ModelStruct
+	Value     interface{}
+}
+
+type changeSubscriber struct {
+	f      func(e *ChangeEvent)
+	models map[*ModelStruct]bool
+}
+
+type changeSubscribers struct {
+	mu          sync.RWMutex
+	subscribers []*changeSubscriber
+}
+
+func (this *changeSubscribers) publish(e *ChangeEvent) {
+	this.mu.RLock()
+	subscribers := this.subscribers
+	this.mu.RUnlock()
+	for _, s := range subscribers {
+		if s.models == nil || s.models[e.Model] {
+			s.f(e)
+		}
+	}
+}
+
+// SubscribeChanges subscribes f to the change events of records of models, or of all models if none is given.
+// The events are published in process, after the transaction commits or rolls back, and f is called
+// synchronously, so it must not block.
+//
+//	unsubscribe := db.SubscribeChanges(func(e *aorm.ChangeEvent) {
+//		if e.Committed {
+//			cache.Delete(aorm.IdOf(e.Value))
+//		}
+//	}, &User{})
+//	defer unsubscribe()
+func (s *DB) SubscribeChanges(f func(e *ChangeEvent), models ...interface{}) (unsubscribe func()) {
+	sub := &changeSubscriber{f: f}
+	if len(models) > 0 {
+		sub.models = map[*ModelStruct]bool{}
+		for _, model := range models {
+			sub.models[StructOf(model)] = true
+		}
+	}
+	changes := s.parent.changes
+	changes.mu.Lock()
+	changes.subscribers = append(changes.subscribers[:len(changes.subscribers):len(changes.subscribers)], sub)
+	changes.mu.Unlock()
+	return func() {
+		changes.mu.Lock()
+		defer changes.mu.Unlock()
+		for i, s := range changes.subscribers {
+			if s == sub {
+				changes.subscribers = append(changes.subscribers[:i:i], changes.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+type txRecord struct {
+	op    Operation
+	model *ModelStruct
+	value interface{}
+}
+
+// txRecords tracks the records changed by transaction, until it completes
+type txRecords struct {
+	// db is the db out of transaction, used to call the hooks
+	db      *DB
+	mu      sync.Mutex
+	records []*txRecord
+	done    bool
+}
+
+func (this *txRecords) add(r *txRecord) bool {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+	if this.done {
+		return false
+	}
+	this.records = append(this.records, r)
+	return true
+}
+
+// flush calls the commit or rollback hooks of tracked records and publishes its change events, once
+func (this *txRecords) flush(committed bool) error {
+	this.mu.Lock()
+	records := this.records
+	this.records, this.done = nil, true
+	this.mu.Unlock()
+
+	var errs Errors
+	for _, r := range records {
+		if err := this.db.completeRecord(r, committed); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return errs
+}
+
+// trackTxRecords starts tracking the records changed by transaction of db. The hooks are called by db out of
+// transaction.
+func (s *DB) trackTxRecords(out *DB) *txRecords {
+	records := &txRecords{db: out.clone()}
+	records.db.Error = nil
+	delete(records.db.values, optKeyTxRecords)
+	s.values[optKeyTxRecords] = records
+	return records
+}
+
+func (s *DB) txRecords() *txRecords {
+	if v, ok := s.values[optKeyTxRecords]; ok {
+		return v.(*txRecords)
+	}
+	return nil
+}
+
+// flushTxRecords calls the commit or rollback hooks of records changed by transaction of db
+func (s *DB) flushTxRecords(committed bool) error {
+	if records := s.txRecords(); records != nil {
+		return records.flush(committed)
+	}
+	return nil
+}
+
+// completeRecord calls the commit or rollback hooks of record and publishes its change event
+func (s *DB) completeRecord(r *txRecord, committed bool) error {
+	var (
+		scope = s.NewModelScope(r.model, r.value)
+		state = "rollback"
+	)
+	scope.db.Error = nil
+	if committed {
+		state = "commit"
+		switch r.op {
+		case OpCreate:
+			scope.CallMethod("AfterCreateCommit")
+		case OpUpdate:
+			scope.CallMethod("AfterUpdateCommit")
+		case OpDelete:
+			scope.CallMethod("AfterDeleteCommit")
+		}
+		scope.CallMethod("AfterCommit")
+	} else {
+		scope.CallMethod("AfterRollback")
+	}
+	s.parent.changes.publish(&ChangeEvent{Operation: r.op, Committed: committed, Model: r.model, Value: r.value})
+	if err := scope.db.Error; err != nil {
+		return errors.Wrapf(err, "after %s %s of %s", r.op, state, r.model.Fqn())
+	}
+	return nil
+}
+
+// trackTxRecord tracks the record of scope changed by operation, until the transaction completes. If there is
+// not transaction, the record is completed now.
+func (scope *Scope) trackTxRecord(op Operation) {
+	if scope.Value == nil || reflect.ValueOf(scope.Value).Kind() == reflect.Ptr && reflect.ValueOf(scope.Value).IsNil() {
+		return
+	}
+	r := &txRecord{op: op, model: scope.Struct(), value: scope.Value}
+	if records := scope.db.txRecords(); records != nil && records.add(r) {
+		return
+	}
+	scope.Err(scope.NewDB().completeRecord(r, true))
+}
@@ -0,0 +1,75 @@
+package aorm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type CommitHookRecord struct {
+	aorm.Model
+	Name string
+
+	created, updated, deleted, committed, rolledBack int
+}
+
+func (this *CommitHookRecord) AfterCreateCommit() { this.created++ }
+func (this *CommitHookRecord) AfterUpdateCommit() { this.updated++ }
+func (this *CommitHookRecord) AfterDeleteCommit() { this.deleted++ }
+func (this *CommitHookRecord) AfterCommit()       { this.committed++ }
+func (this *CommitHookRecord) AfterRollback()     { this.rolledBack++ }
+
+func TestAfterCommitHooks(t *testing.T) {
+	DB.DropTableIfExists(&CommitHookRecord{})
+	DB.AutoMigrate(&CommitHookRecord{})
+
+	var events []aorm.ChangeEvent
+	unsubscribe := DB.SubscribeChanges(func(e *aorm.ChangeEvent) {
+		events = append(events, *e)
+	}, &CommitHookRecord{})
+	defer unsubscribe()
+
+	record := CommitHookRecord{Name: "r1"}
+	if err := DB.Transaction(func(tx *aorm.DB) error {
+		if err := tx.Save(&record).Error; err != nil {
+			return err
+		}
+		if record.created != 0 || record.committed != 0 {
+			t.Errorf("commit hooks should not be called before commit")
+		}
+		return tx.Model(&record).Update("name", "r2").Error
+	}); err != nil {
+		t.Fatalf("no error should happen, but got %v", err)
+	}
+	if record.created != 1 || record.updated != 1 || record.committed != 2 || record.rolledBack != 0 {
+		t.Errorf("commit hooks should be called once per change after commit, but got %+v", record)
+	}
+	if len(events) != 2 || events[0].Operation != aorm.OpCreate || events[1].Operation != aorm.OpUpdate || !events[0].Committed {
+		t.Errorf("change events should be published after commit, but got %v", events)
+	}
+
+	events = nil
+	other := CommitHookRecord{Name: "r3"}
+	DB.Transaction(func(tx *aorm.DB) error {
+		tx.Save(&other)
+		return errors.New("abort")
+	})
+	if other.created != 0 || other.committed != 0 || other.rolledBack != 1 {
+		t.Errorf("rollback hooks should be called after rollback, but got %+v", other)
+	}
+	if len(events) != 1 || events[0].Committed {
+		t.Errorf("rolled back change event should be published, but got %v", events)
+	}
+
+	events = nil
+	if err := DB.Delete(&record).Error; err != nil {
+		t.Fatalf("no error should happen, but got %v", err)
+	}
+	if record.deleted != 1 || record.committed != 3 {
+		t.Errorf("commit hooks should be called after delete out of transaction, but got %+v", record)
+	}
+	if len(events) != 1 || events[0].Operation != aorm.OpDelete {
+		t.Errorf("delete change event should be published, but got %v", events)
+	}
+}
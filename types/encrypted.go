@@ -0,0 +1,401 @@
+package types
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+const (
+	encryptedVersion byte = 1
+
+	encryptedFlagDeterministic byte = 1
+)
+
+// ErrEncryptedKeyNotFound is returned when the keyring does not have the key used to encrypt value
+var ErrEncryptedKeyNotFound = errors.New("encrypted: key not found")
+
+type (
+	// Keyring provides the keys of encrypted values. Keys must have 16, 24 or 32 bytes (AES-128, AES-192
+	// or AES-256).
+	Keyring interface {
+		// Key returns the key of id
+		Key(id string) (key []byte, err error)
+		// Current returns the id and key used to encrypt new values
+		Current() (id string, key []byte, err error)
+	}
+
+	// StaticKeyring is a Keyring of in memory keys
+	StaticKeyring struct {
+		CurrentID string
+		Keys      map[string][]byte
+	}
+
+	// Encrypted is a value encrypted with AES-GCM on write and decrypted on scan, using the keys of
+	// DefaultKeyring. The key id is stored with the ciphertext, so old values still are decrypted after key
+	// rotation. Strings and byte slices are encrypted as is, other types as JSON.
+	//
+	// If Deterministic is true, the nonce is derived from value, so equal values under the same key have
+	// equal ciphertexts, allowing equality lookups:
+	//
+	//	db.Where("ssn = ?", types.EncryptedString{Data: "123", Deterministic: true}).First(&person)
+	//
+	// Deterministic mode leaks equality of values, use it only for lookup columns.
+	Encrypted[T any] struct {
+		Data          T
+		Deterministic bool
+	}
+
+	// EncryptedString is an encrypted string
+	EncryptedString = Encrypted[string]
+
+	// EncryptedAssigner is the assigner of Encrypted[T]
+	EncryptedAssigner[T any] struct {
+	}
+)
+
+var (
+	keyringMu      sync.RWMutex
+	defaultKeyring Keyring
+)
+
+func init() {
+	RegisterEncrypted[string]()
+	RegisterEncrypted[[]byte]()
+}
+
+// RegisterEncrypted registers the assigner of Encrypted[T]
+func RegisterEncrypted[T any]() {
+	aorm.Register(EncryptedAssigner[T]{})
+}
+
+// SetKeyring set the keyring used by encrypted values
+func SetKeyring(keyring Keyring) {
+	keyringMu.Lock()
+	defer keyringMu.Unlock()
+	defaultKeyring = keyring
+}
+
+// DefaultKeyring returns the keyring used by encrypted values
+func DefaultKeyring() Keyring {
+	keyringMu.RLock()
+	defer keyringMu.RUnlock()
+	return defaultKeyring
+}
+
+func (this StaticKeyring) Key(id string) ([]byte, error) {
+	if key, ok := this.Keys[id]; ok {
+		return key, nil
+	}
+	return nil, errors.Wrapf(ErrEncryptedKeyNotFound, "key %q", id)
+}
+
+func (this StaticKeyring) Current() (id string, key []byte, err error) {
+	key, err = this.Key(this.CurrentID)
+	return this.CurrentID, key, err
+}
+
+func (this Encrypted[T]) IsZero() bool {
+	return reflect.ValueOf(&this.Data).Elem().IsZero()
+}
+
+func (this Encrypted[T]) String() string {
+	return aorm.HiddenStringerValue
+}
+
+func (Encrypted[T]) CanProtectStringer() bool {
+	return true
+}
+
+func (this Encrypted[T]) Value() (driver.Value, error) {
+	if this.IsZero() {
+		return nil, nil
+	}
+	keyring := DefaultKeyring()
+	if keyring == nil {
+		return nil, errors.New("encrypted: keyring is not set")
+	}
+	plaintext, err := this.marshal()
+	if err != nil {
+		return nil, err
+	}
+	return Encrypt(keyring, plaintext, this.Deterministic)
+}
+
+func (this *Encrypted[T]) Scan(src interface{}) (err error) {
+	*this = Encrypted[T]{}
+	var ciphertext []byte
+	switch t := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		ciphertext = t
+	case string:
+		ciphertext = []byte(t)
+	default:
+		return fmt.Errorf("types.Encrypted.Scan(%T): unexpected type", src)
+	}
+	if len(ciphertext) == 0 {
+		return nil
+	}
+	keyring := DefaultKeyring()
+	if keyring == nil {
+		return errors.New("encrypted: keyring is not set")
+	}
+	var plaintext []byte
+	if plaintext, this.Deterministic, err = Decrypt(keyring, ciphertext); err != nil {
+		return
+	}
+	return this.unmarshal(plaintext)
+}
+
+func (this Encrypted[T]) marshal() ([]byte, error) {
+	switch t := interface{}(this.Data).(type) {
+	case string:
+		return []byte(t), nil
+	case []byte:
+		return t, nil
+	default:
+		return json.Marshal(this.Data)
+	}
+}
+
+func (this *Encrypted[T]) unmarshal(plaintext []byte) error {
+	switch t := interface{}(&this.Data).(type) {
+	case *string:
+		*t = string(plaintext)
+		return nil
+	case *[]byte:
+		*t = plaintext
+		return nil
+	default:
+		return json.Unmarshal(plaintext, &this.Data)
+	}
+}
+
+func (EncryptedAssigner[T]) Valuer(_ aorm.Dialector, value interface{}) driver.Valuer {
+	return value.(Encrypted[T])
+}
+
+func (EncryptedAssigner[T]) Scaner(_ aorm.Dialector, dest reflect.Value) aorm.Scanner {
+	return dest.Addr().Interface().(*Encrypted[T])
+}
+
+func (EncryptedAssigner[T]) SQLType(d aorm.Dialector) string {
+	switch d.GetName() {
+	case "postgres":
+		return "BYTEA"
+	case "mssql":
+		return "VARBINARY(MAX)"
+	}
+	return "BLOB"
+}
+
+func (EncryptedAssigner[T]) SQLSize(_ aorm.Dialector) int {
+	return 0
+}
+
+func (EncryptedAssigner[T]) Type() reflect.Type {
+	return reflect.TypeOf(Encrypted[T]{})
+}
+
+// Encrypt encrypts plaintext with the current key of keyring. The ciphertext format is
+// `version | flags | len(key id) | key id | nonce | sealed data`, and the header is authenticated.
+func Encrypt(keyring Keyring, plaintext []byte, deterministic bool) (ciphertext []byte, err error) {
+	var (
+		id  string
+		key []byte
+	)
+	if id, key, err = keyring.Current(); err != nil {
+		return
+	}
+	if len(id) > 255 {
+		return nil, errors.Errorf("encrypted: key id %q is too long", id)
+	}
+	return encryptWithKey(id, key, plaintext, deterministic)
+}
+
+func encryptWithKey(id string, key, plaintext []byte, deterministic bool) (ciphertext []byte, err error) {
+	var aead cipher.AEAD
+	if aead, err = newAEAD(key); err != nil {
+		return
+	}
+
+	header := []byte{encryptedVersion, 0, byte(len(id))}
+	header = append(header, id...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if deterministic {
+		header[1] |= encryptedFlagDeterministic
+		copy(nonce, deterministicNonce(key, plaintext))
+	} else if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return
+	}
+
+	ciphertext = make([]byte, 0, len(header)+len(nonce)+len(plaintext)+aead.Overhead())
+	ciphertext = append(append(ciphertext, header...), nonce...)
+	return aead.Seal(ciphertext, nonce, plaintext, header), nil
+}
+
+// Decrypt decrypts the ciphertext created by Encrypt, using the key of keyring stored in ciphertext
+func Decrypt(keyring Keyring, ciphertext []byte) (plaintext []byte, deterministic bool, err error) {
+	var (
+		id     string
+		header []byte
+		key    []byte
+		aead   cipher.AEAD
+	)
+	if id, header, deterministic, err = parseEncryptedHeader(ciphertext); err != nil {
+		return
+	}
+	if key, err = keyring.Key(id); err != nil {
+		return
+	}
+	if aead, err = newAEAD(key); err != nil {
+		return
+	}
+	data := ciphertext[len(header):]
+	if len(data) < aead.NonceSize() {
+		return nil, false, errors.New("encrypted: invalid ciphertext")
+	}
+	if plaintext, err = aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], header); err != nil {
+		return nil, false, errors.Wrap(err, "encrypted: decrypt")
+	}
+	return
+}
+
+// KeyIDOf returns the id of key used to encrypt the ciphertext
+func KeyIDOf(ciphertext []byte) (id string, err error) {
+	id, _, _, err = parseEncryptedHeader(ciphertext)
+	return
+}
+
+func parseEncryptedHeader(ciphertext []byte) (id string, header []byte, deterministic bool, err error) {
+	if len(ciphertext) < 3 || ciphertext[0] != encryptedVersion || len(ciphertext) < 3+int(ciphertext[2]) {
+		return "", nil, false, errors.New("encrypted: invalid ciphertext")
+	}
+	header = ciphertext[:3+int(ciphertext[2])]
+	return string(header[3:]), header, header[1]&encryptedFlagDeterministic != 0, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "encrypted")
+	}
+	return cipher.NewGCM(block)
+}
+
+// deterministicNonce derives the nonce from a sub key of key and plaintext (SIV like)
+func deterministicNonce(key, plaintext []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("aorm.encrypted.nonce"))
+	mac = hmac.New(sha256.New, mac.Sum(nil))
+	mac.Write(plaintext)
+	return mac.Sum(nil)
+}
+
+// ReEncryptBatchSize is the number of rows read by each batch of ReEncrypt
+var ReEncryptBatchSize = 500
+
+// ReEncrypt re-encrypts the values of column of model table that are not encrypted with the current key of
+// keyring, keeping the deterministic mode, e.g. after adding a new current key. The model must have one primary
+// field. Returns the number of updated rows.
+//
+//	n, err := types.ReEncrypt(db, &Person{}, "SSN", keyring)
+func ReEncrypt(db *aorm.DB, model interface{}, column string, keyring Keyring) (n int64, err error) {
+	var (
+		scope     = db.NewScope(model)
+		ms        = scope.Struct()
+		currentID string
+		lastPK    interface{}
+	)
+	if len(ms.PrimaryFields) != 1 {
+		return 0, errors.Errorf("encrypted: re-encrypt requires one primary field of %s", ms.Type)
+	}
+	if field, ok := ms.FieldByName(column); ok {
+		column = field.DBName
+	}
+	if currentID, _, err = keyring.Current(); err != nil {
+		return
+	}
+
+	var (
+		pk  = scope.Quote(ms.PrimaryFields[0].DBName)
+		col = scope.Quote(column)
+	)
+
+	for {
+		var (
+			pks     []interface{}
+			updates [][]byte
+			count   int
+			// unscoped, so the soft deleted rows are re-encrypted too
+			q = db.Unscoped().Model(model).Select(pk + ", " + col).Where(col + " IS NOT NULL").Order(pk).Limit(ReEncryptBatchSize)
+		)
+		if lastPK != nil {
+			q = q.Where(pk+" > ?", lastPK)
+		}
+
+		rows, err := q.Rows()
+		if err != nil {
+			return n, err
+		}
+		for rows.Next() {
+			var (
+				id         interface{}
+				ciphertext []byte
+			)
+			if err = rows.Scan(&id, &ciphertext); err != nil {
+				rows.Close()
+				return n, err
+			}
+			count++
+			lastPK = id
+			var keyID string
+			if keyID, err = KeyIDOf(ciphertext); err != nil {
+				rows.Close()
+				return n, errors.Wrapf(err, "re-encrypt %v = %v", pk, id)
+			}
+			if keyID != currentID {
+				pks = append(pks, id)
+				updates = append(updates, ciphertext)
+			}
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return n, err
+		}
+
+		for i, ciphertext := range updates {
+			plaintext, deterministic, err := Decrypt(keyring, ciphertext)
+			if err != nil {
+				return n, errors.Wrapf(err, "re-encrypt %v = %v", pk, pks[i])
+			}
+			if ciphertext, err = Encrypt(keyring, plaintext, deterministic); err != nil {
+				return n, err
+			}
+			if err = db.Unscoped().Opt(aorm.OptForceSingleUpdate()).Model(model).Where(pk+" = ?", pks[i]).
+				UpdateColumn(column, ciphertext).Error; err != nil {
+				return n, err
+			}
+			n++
+		}
+
+		if count < ReEncryptBatchSize {
+			return n, nil
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package types_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/moisespsena-go/aorm"
+	_ "github.com/moisespsena-go/aorm/dialects/sqlite"
+	"github.com/moisespsena-go/aorm/types"
+)
+
+var (
+	key1 = bytes.Repeat([]byte{1}, 32)
+	key2 = bytes.Repeat([]byte{2}, 32)
+)
+
+type EncryptedPerson struct {
+	ID   int `sql:"primary_key;auto_increment"`
+	Name string
+	SSN  types.EncryptedString
+}
+
+func TestEncryptedRoundTrip(t *testing.T) {
+	keyring := types.StaticKeyring{CurrentID: "k1", Keys: map[string][]byte{"k1": key1}}
+	types.SetKeyring(keyring)
+	defer types.SetKeyring(nil)
+
+	value, err := types.EncryptedString{Data: "123-45-6789"}.Value()
+	if err != nil {
+		t.Fatalf("no error should happen when encrypt, but got %v", err)
+	}
+	ciphertext := value.([]byte)
+	if bytes.Contains(ciphertext, []byte("123-45-6789")) {
+		t.Errorf("ciphertext should not contain the plaintext")
+	}
+	if id, err := types.KeyIDOf(ciphertext); err != nil || id != "k1" {
+		t.Errorf("key id should be k1, but got %q, %v", id, err)
+	}
+
+	var s types.EncryptedString
+	if err = s.Scan(ciphertext); err != nil || s.Data != "123-45-6789" || s.Deterministic {
+		t.Errorf("value should be decrypted, but got %+v, %v", s, err)
+	}
+
+	var m types.Encrypted[map[string]int]
+	if value, err = (types.Encrypted[map[string]int]{Data: map[string]int{"a": 1}}).Value(); err != nil {
+		t.Fatalf("no error should happen when encrypt JSON, but got %v", err)
+	}
+	if err = m.Scan(value); err != nil || m.Data["a"] != 1 {
+		t.Errorf("JSON value should be decrypted, but got %+v, %v", m, err)
+	}
+
+	if value, _ = (types.EncryptedString{}).Value(); value != nil {
+		t.Errorf("zero value should be stored as NULL, but got %v", value)
+	}
+}
+
+func TestEncryptedDeterministic(t *testing.T) {
+	keyring := types.StaticKeyring{CurrentID: "k1", Keys: map[string][]byte{"k1": key1}}
+
+	a, _ := types.Encrypt(keyring, []byte("value"), true)
+	b, _ := types.Encrypt(keyring, []byte("value"), true)
+	if !bytes.Equal(a, b) {
+		t.Errorf("deterministic ciphertexts of equal values should be equal")
+	}
+	if c, _ := types.Encrypt(keyring, []byte("other"), true); bytes.Equal(a, c) {
+		t.Errorf("deterministic ciphertexts of different values should differ")
+	}
+	if _, deterministic, err := types.Decrypt(keyring, a); err != nil || !deterministic {
+		t.Errorf("deterministic flag should be decrypted, but got %v, %v", deterministic, err)
+	}
+
+	a, _ = types.Encrypt(keyring, []byte("value"), false)
+	b, _ = types.Encrypt(keyring, []byte("value"), false)
+	if bytes.Equal(a, b) {
+		t.Errorf("randomized ciphertexts of equal values should differ")
+	}
+}
+
+func TestEncryptedKeyRotation(t *testing.T) {
+	keyring := types.StaticKeyring{CurrentID: "k1", Keys: map[string][]byte{"k1": key1}}
+	old, _ := types.Encrypt(keyring, []byte("value"), false)
+
+	keyring.CurrentID, keyring.Keys["k2"] = "k2", key2
+	ciphertext, _ := types.Encrypt(keyring, []byte("value"), false)
+	if id, _ := types.KeyIDOf(ciphertext); id != "k2" {
+		t.Errorf("new values should be encrypted with current key, but got %q", id)
+	}
+	if plaintext, _, err := types.Decrypt(keyring, old); err != nil || string(plaintext) != "value" {
+		t.Errorf("old values should be decrypted after rotation, but got %q, %v", plaintext, err)
+	}
+
+	delete(keyring.Keys, "k1")
+	if _, _, err := types.Decrypt(keyring, old); errors.Cause(err) != types.ErrEncryptedKeyNotFound {
+		t.Errorf("removed key should not be found, but got %v", err)
+	}
+}
+
+func TestEncryptedTampered(t *testing.T) {
+	keyring := types.StaticKeyring{CurrentID: "k1", Keys: map[string][]byte{"k1": key1}}
+	ciphertext, _ := types.Encrypt(keyring, []byte("value"), false)
+
+	tampered := append([]byte{}, ciphertext...)
+	tampered[len(tampered)-1] ^= 1
+	if _, _, err := types.Decrypt(keyring, tampered); err == nil {
+		t.Errorf("tampered ciphertext should not be decrypted")
+	}
+
+	wrong := types.StaticKeyring{CurrentID: "k1", Keys: map[string][]byte{"k1": key2}}
+	if _, _, err := types.Decrypt(wrong, ciphertext); err == nil {
+		t.Errorf("ciphertext should not be decrypted with wrong key")
+	}
+
+	if _, _, err := types.Decrypt(keyring, []byte("plain")); err == nil {
+		t.Errorf("invalid ciphertext should not be decrypted")
+	}
+}
+
+func TestReEncrypt(t *testing.T) {
+	keyring := types.StaticKeyring{CurrentID: "k1", Keys: map[string][]byte{"k1": key1}}
+	types.SetKeyring(keyring)
+	defer types.SetKeyring(nil)
+
+	db, err := aorm.Open("sqlite3", filepath.Join(os.TempDir(), "aorm_types.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.DropTableIfExists(&EncryptedPerson{})
+	migrator := db.Migrator()
+	if err = migrator.AutoMigrate(&EncryptedPerson{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = migrator.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(size int) {
+		types.ReEncryptBatchSize = size
+	}(types.ReEncryptBatchSize)
+	types.ReEncryptBatchSize = 2
+
+	for _, p := range []*EncryptedPerson{
+		{Name: "a", SSN: types.EncryptedString{Data: "1"}},
+		{Name: "b", SSN: types.EncryptedString{Data: "2", Deterministic: true}},
+		{Name: "c"},
+	} {
+		if err = db.Create(p).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	keyring.CurrentID, keyring.Keys["k2"] = "k2", key2
+	types.SetKeyring(keyring)
+	d := &EncryptedPerson{Name: "d", SSN: types.EncryptedString{Data: "4"}}
+	if err = db.Create(d).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := types.ReEncrypt(db, &EncryptedPerson{}, "SSN", keyring)
+	if err != nil || n != 2 {
+		t.Fatalf("should re-encrypt 2 rows, but got %v, %v", n, err)
+	}
+
+	rows, err := db.Model(&EncryptedPerson{}).Select("name, ssn").Where("ssn IS NOT NULL").Order("id").Rows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			name       string
+			ciphertext []byte
+		)
+		rows.Scan(&name, &ciphertext)
+		if id, _ := types.KeyIDOf(ciphertext); id != "k2" {
+			t.Errorf("%s should be encrypted with current key, but got %q", name, id)
+		}
+	}
+
+	var people []EncryptedPerson
+	db.Order("id").Find(&people)
+	if len(people) != 4 || people[0].SSN.Data != "1" || people[1].SSN.Data != "2" || !people[1].SSN.Deterministic || people[3].SSN.Data != "4" {
+		t.Errorf("re-encrypted values should be decrypted keeping deterministic mode, but got %+v", people)
+	}
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+// JSON is a column of Go type T stored as JSON. The column type is `JSONB` on postgres, `JSON` on mysql
+// and `TEXT` on other dialects. Use `aorm.JSONPath` to query it:
+//
+//	type User struct {
+//		aorm.Model
+//		Settings types.JSON[Settings]
+//	}
+//
+//	db.Where(aorm.JSONPath("Settings", "theme.color").Eq("dark")).Find(&users)
+type JSON[T any] struct {
+	Data T
+}
+
+// NewJSON returns JSON of data
+func NewJSON[T any](data T) JSON[T] {
+	return JSON[T]{data}
+}
+
+func (JSON[T]) AormDataType(dialect aorm.Dialector) string {
+	switch dialect.GetName() {
+	case "postgres":
+		return "JSONB"
+	case "mysql":
+		return "JSON"
+	case "mssql":
+		// JSON functions of mssql does not accept TEXT
+		return "NVARCHAR(MAX)"
+	default:
+		return "TEXT"
+	}
+}
+
+func (this JSON[T]) Value() (driver.Value, error) {
+	b, err := json.Marshal(this.Data)
+	if err != nil {
+		return nil, err
+	}
+	if string(b) == "null" {
+		return nil, nil
+	}
+	return string(b), nil
+}
+
+func (this *JSON[T]) Scan(src interface{}) error {
+	*this = JSON[T]{}
+	switch t := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		if len(t) == 0 {
+			return nil
+		}
+		return json.Unmarshal(t, &this.Data)
+	case string:
+		if t == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(t), &this.Data)
+	default:
+		return fmt.Errorf("types.JSON.Scan(%T): unexpected type", src)
+	}
+}
+
+func (this JSON[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.Data)
+}
+
+func (this *JSON[T]) UnmarshalJSON(b []byte) error {
+	return json.Unmarshal(b, &this.Data)
+}
@@ -426,10 +426,6 @@ func toString(str interface{}) string {
 		return strings.Join(results, "_")
 	case []byte:
 		return string(t)
-	case interface{ AsBytes() []byte }:
-		return toString(t.AsBytes())
-	case interface{ Bytes() []byte }:
-		return toString(t.Bytes())
 	case reflect.Value:
 		if t.IsValid() {
 			switch t.Kind() {
@@ -439,12 +435,21 @@ func toString(str interface{}) string {
 				newV.Elem().Set(t)
 				t = newV.Elem().Slice(0, l)
 				return toString(t.Interface())
+			case reflect.Slice:
+				if t.Type().Elem().Kind() == reflect.Uint8 {
+					return string(t.Bytes())
+				}
+				return fmt.Sprintf("%v", t.Interface())
 			default:
 				if t = reflect.Indirect(t); t.IsValid() {
 					return fmt.Sprintf("%v", t.Interface())
 				}
 			}
 		}
+	case interface{ AsBytes() []byte }:
+		return toString(t.AsBytes())
+	case interface{ Bytes() []byte }:
+		return toString(t.Bytes())
 	default:
 		return toString(reflect.ValueOf(str))
 	}
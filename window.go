@@ -0,0 +1,164 @@
+package aorm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WindowRankColumn is the column of window function used by TopPerGroup
+const WindowRankColumn = "aorm_window_rank"
+
+// Window is a window function expression builder, used as query of ExtraSelect. Arguments, partitions and
+// orders accepts model field names, db names or raw sql expressions. Orders prefixed with `-` are descending.
+//
+//	db.ExtraSelect("rank", []interface{}{int64(0)}, aorm.RowNumber().PartitionBy("UserID").OrderBy("-Total")).Find(&orders)
+//	db.ExtraSelect("running", []interface{}{float64(0)}, aorm.WindowFunc("SUM", "Total").PartitionBy("UserID").OrderBy("CreatedAt")).Find(&orders)
+type Window struct {
+	fn        string
+	args      []interface{}
+	partition []string
+	orders    []string
+	frame     string
+	alias     string
+}
+
+// WindowFunc creates window expression of function fn with args. String args are resolved as fields.
+func WindowFunc(fn string, args ...interface{}) *Window {
+	return &Window{fn: fn, args: args}
+}
+
+// RowNumber creates `ROW_NUMBER() OVER (...)` window expression
+func RowNumber() *Window {
+	return WindowFunc("ROW_NUMBER")
+}
+
+// Rank creates `RANK() OVER (...)` window expression
+func Rank() *Window {
+	return WindowFunc("RANK")
+}
+
+// DenseRank creates `DENSE_RANK() OVER (...)` window expression
+func DenseRank() *Window {
+	return WindowFunc("DENSE_RANK")
+}
+
+// Lag creates `LAG(field, offset) OVER (...)` window expression
+func Lag(field string, offset int) *Window {
+	return WindowFunc("LAG", field, offset)
+}
+
+// Lead creates `LEAD(field, offset) OVER (...)` window expression
+func Lead(field string, offset int) *Window {
+	return WindowFunc("LEAD", field, offset)
+}
+
+// PartitionBy set the `PARTITION BY` fields
+func (this *Window) PartitionBy(fields ...string) *Window {
+	this.partition = append(this.partition, fields...)
+	return this
+}
+
+// OrderBy set the `ORDER BY` fields. Fields prefixed with `-` are descending.
+func (this *Window) OrderBy(fields ...string) *Window {
+	this.orders = append(this.orders, fields...)
+	return this
+}
+
+// Frame set the frame clause, e.g. `ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW`
+func (this *Window) Frame(frame string) *Window {
+	this.frame = frame
+	return this
+}
+
+// As set the column alias
+func (this *Window) As(alias string) *Window {
+	this.alias = alias
+	return this
+}
+
+func (this *Window) column(scope *Scope, name string) string {
+	if field, ok := scope.Struct().FieldByName(name); ok && field.IsNormal {
+		return scope.QuotedTableName() + "." + scope.Quote(field.DBName)
+	}
+	return name
+}
+
+// WhereClause implements WhereClauser
+func (this *Window) WhereClause(scope *Scope) (result Query) {
+	var args, over []string
+	for _, arg := range this.args {
+		switch t := arg.(type) {
+		case string:
+			args = append(args, this.column(scope, t))
+		case int:
+			args = append(args, fmt.Sprint(t))
+		default:
+			args = append(args, "?")
+			result.AddArgs(arg)
+		}
+	}
+
+	if len(this.partition) > 0 {
+		var columns = make([]string, len(this.partition))
+		for i, name := range this.partition {
+			columns[i] = this.column(scope, name)
+		}
+		over = append(over, "PARTITION BY "+strings.Join(columns, ", "))
+	}
+
+	if len(this.orders) > 0 {
+		var columns = make([]string, len(this.orders))
+		for i, name := range this.orders {
+			if strings.HasPrefix(name, "-") {
+				columns[i] = this.column(scope, name[1:]) + " DESC"
+			} else {
+				columns[i] = this.column(scope, name)
+			}
+		}
+		over = append(over, "ORDER BY "+strings.Join(columns, ", "))
+	}
+
+	if this.frame != "" {
+		over = append(over, this.frame)
+	}
+
+	result.Query = fmt.Sprintf("%v(%v) OVER (%v)", this.fn, strings.Join(args, ", "), strings.Join(over, " "))
+	if this.alias != "" {
+		result.Query += " AS " + scope.Quote(this.alias)
+	}
+	return
+}
+
+type topPerGroupQuery struct {
+	db     *DB
+	window *Window
+}
+
+func (this *topPerGroupQuery) WhereClause(scope *Scope) Query {
+	window := *this.window
+	inner := this.db.Select([]interface{}{IQ("{}.*"), window.As(WindowRankColumn)}).NewScope(scope.Value)
+	inner.InstanceSet("skip_bindvar", true)
+	inner.prepareQuerySQL()
+	scope.Err(inner.db.Error)
+	return inner.Query
+}
+
+// TopPerGroup find the first n records of each group ranked by window, e.g. the last 3 orders of each user.
+// Conditions added before TopPerGroup filter records before ranking. The rank is available as WindowRankColumn.
+//
+//	db.Where("paid").TopPerGroup(3, aorm.RowNumber().PartitionBy("UserID").OrderBy("-CreatedAt")).Find(&orders)
+func (s *DB) TopPerGroup(n int, window *Window) *DB {
+	// the conditions filter records in the subquery, the rest of search applies to ranked records
+	inner := s.clone()
+	inner.search.orders, inner.search.limit, inner.search.offset = nil, -1, -1
+	inner.search.preload, inner.search.inlinePreload = nil, nil
+	inner.search.extraSelects, inner.search.extraSelectsFields = nil, nil
+
+	outer := s.clone()
+	outer.search = outer.search.resetConditions()
+	outer.search.db = outer
+	outer.search.joinConditions, outer.search.havingConditions, outer.search.group = nil, nil, ""
+	outer.search.FromQuery(&Query{Query: "?", Args: []interface{}{&topPerGroupQuery{inner, window}}}, "")
+	outer.search.Where(fmt.Sprintf("%v <= ?", Quote(s.Dialect(), WindowRankColumn)), n)
+	return outer
+}
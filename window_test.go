@@ -0,0 +1,62 @@
+package aorm_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/moisespsena-go/aorm"
+)
+
+type WindowScore struct {
+	aorm.ExtraSelectModel
+	ID     int
+	Player string
+	Points int
+}
+
+func TestWindowFunctions(t *testing.T) {
+	DB.DropTableIfExists(&WindowScore{})
+	DB.AutoMigrate(&WindowScore{})
+	for _, score := range []WindowScore{
+		{Player: "a", Points: 10}, {Player: "a", Points: 30}, {Player: "a", Points: 20},
+		{Player: "b", Points: 5}, {Player: "b", Points: 15},
+	} {
+		DB.Save(&score)
+	}
+
+	var scores []WindowScore
+	if err := DB.ExtraSelect("rank", []interface{}{int64(0)}, aorm.RowNumber().PartitionBy("Player").OrderBy("-Points")).
+		Order("player, points DESC").Find(&scores).Error; err != nil {
+		t.Fatalf("no error should happen when select window function, but got %v", err)
+	}
+
+	var ranks []interface{}
+	for _, score := range scores {
+		if result, ok := score.GetAormExtraScannedValue("rank"); ok {
+			ranks = append(ranks, result.Values[0])
+		}
+	}
+	if !reflect.DeepEqual(ranks, []interface{}{int64(1), int64(2), int64(3), int64(1), int64(2)}) {
+		t.Errorf("window ranks should be scanned into extra results, but got %v", ranks)
+	}
+
+	scores = nil
+	DB.TopPerGroup(2, aorm.RowNumber().PartitionBy("Player").OrderBy("-Points")).Order("player, points DESC").Find(&scores)
+	var points []int
+	for _, score := range scores {
+		points = append(points, score.Points)
+	}
+	if !reflect.DeepEqual(points, []int{30, 20, 15, 5}) {
+		t.Errorf("top 2 scores per player should be found, but got %v", points)
+	}
+
+	scores, points = nil, nil
+	DB.Where("points > ?", 5).Order("points DESC").Limit(1).
+		TopPerGroup(1, aorm.RowNumber().PartitionBy("Player").OrderBy("Points")).Find(&scores)
+	for _, score := range scores {
+		points = append(points, score.Points)
+	}
+	if !reflect.DeepEqual(points, []int{15}) {
+		t.Errorf("top per group should keep order and limit of search, but got %v", points)
+	}
+}